@@ -0,0 +1,432 @@
+package mswagger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	ChangeAdded   = "Added"
+	ChangeRemoved = "Removed"
+	ChangeChanged = "Changed"
+)
+
+// Changelog lists the differences between two Swagger objects.
+type Changelog struct {
+	From, To    string
+	Operations  []*OperationChange
+	Definitions []*DefinitionChange
+}
+
+// OperationChange is a change of a single operation, reported under Tag.
+type OperationChange struct {
+	Tag     string
+	Method  string
+	Path    string
+	Kind    string
+	Details []string
+}
+
+// DefinitionChange is a change of a single model definition.
+type DefinitionChange struct {
+	Name    string
+	Kind    string
+	Details []string
+}
+
+// GenerateChangelog checks out the revisions from and to of the git repository
+// containing params.ApiPackage, parses both and returns the rendered changelog.
+func GenerateChangelog(params Params, from, to string) (string, error) {
+	fromSwagger, err := ParseRevision(params, from)
+	if err != nil {
+		return "", err
+	}
+	toSwagger, err := ParseRevision(params, to)
+	if err != nil {
+		return "", err
+	}
+
+	changelog := DiffSwagger(fromSwagger, toSwagger)
+	changelog.From = from
+	changelog.To = to
+	return changelog.String(), nil
+}
+
+// ParseRevision parses params.ApiPackage as it is at the git revision rev.
+// The revision is checked out in a temporary worktree which is placed in a
+// temporary GOPATH, so that imports of the repository resolve to the same revision.
+func ParseRevision(params Params, rev string) (_ *SwaggerObject, err error) {
+	defer recoverParseError(&err)
+
+	if params.ApiPackage == "" {
+		return nil, errors.New("ApiPackage was required.")
+	}
+
+	parser := NewParser()
+	parser.ApiPackage = params.ApiPackage
+	parser.Gopath = params.Gopath
+	pkgRealPath := parser.CheckRealPackagePath(params.ApiPackage)
+	if pkgRealPath == "" {
+		return nil, fmt.Errorf("Can not find package %s", params.ApiPackage)
+	}
+
+	topLevel, err := git(pkgRealPath, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	if evalutedPath, err := filepath.EvalSymlinks(topLevel); err == nil {
+		topLevel = evalutedPath
+	}
+	relPath, err := filepath.Rel(topLevel, pkgRealPath)
+	if err != nil {
+		return nil, err
+	}
+	relPath = filepath.ToSlash(relPath)
+
+	// The import path of the repository root
+	repoPackage := params.ApiPackage
+	if relPath != "." {
+		if !strings.HasSuffix(params.ApiPackage, "/"+relPath) {
+			return nil, fmt.Errorf("Can not map package %s to the git repository %s", params.ApiPackage, topLevel)
+		}
+		repoPackage = strings.TrimSuffix(params.ApiPackage, "/"+relPath)
+	}
+
+	tmpGopath, err := ioutil.TempDir("", "mswagger")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpGopath)
+
+	worktree := filepath.Join(tmpGopath, "src", filepath.FromSlash(repoPackage))
+	if err := os.MkdirAll(filepath.Dir(worktree), 0777); err != nil {
+		return nil, err
+	}
+	if _, err := git(topLevel, "worktree", "add", "--detach", worktree, rev); err != nil {
+		return nil, err
+	}
+	defer git(topLevel, "worktree", "remove", "--force", worktree)
+
+	if filepath.IsAbs(params.MainApiFile) {
+		if relMain, err := filepath.Rel(topLevel, params.MainApiFile); err == nil && !strings.HasPrefix(relMain, "..") {
+			params.MainApiFile = filepath.Join(worktree, relMain)
+		}
+	}
+
+	gopath := params.Gopath
+	if gopath == "" {
+		gopath = os.Getenv("GOPATH")
+	}
+	params.Gopath = tmpGopath + string(filepath.ListSeparator) + gopath
+
+	revParser, err := ParseSwagger(params)
+	if err != nil {
+		return nil, err
+	}
+	return revParser.Swagger, nil
+}
+
+func git(dir string, args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// DiffSwagger compares the operations and definitions of from and to.
+func DiffSwagger(from, to *SwaggerObject) *Changelog {
+	changelog := &Changelog{}
+
+	fromOperations := swaggerOperations(from)
+	toOperations := swaggerOperations(to)
+	for _, key := range sortedOperationKeys(fromOperations, toOperations) {
+		fromOperation, inFrom := fromOperations[key]
+		toOperation, inTo := toOperations[key]
+		method, path := splitOperationKey(key)
+
+		switch {
+		case !inFrom:
+			for _, tag := range operationTags(toOperation) {
+				changelog.Operations = append(changelog.Operations, &OperationChange{Tag: tag, Method: method, Path: path, Kind: ChangeAdded})
+			}
+		case !inTo:
+			for _, tag := range operationTags(fromOperation) {
+				changelog.Operations = append(changelog.Operations, &OperationChange{Tag: tag, Method: method, Path: path, Kind: ChangeRemoved})
+			}
+		default:
+			if details := diffOperation(fromOperation, toOperation); len(details) > 0 {
+				for _, tag := range operationTags(toOperation) {
+					changelog.Operations = append(changelog.Operations, &OperationChange{Tag: tag, Method: method, Path: path, Kind: ChangeChanged, Details: details})
+				}
+			}
+		}
+	}
+
+	var fromDefinitions, toDefinitions map[string]*SchemaObject
+	if from != nil {
+		fromDefinitions = from.Definitions
+	}
+	if to != nil {
+		toDefinitions = to.Definitions
+	}
+	names := map[string]bool{}
+	for name, _ := range fromDefinitions {
+		names[name] = true
+	}
+	for name, _ := range toDefinitions {
+		names[name] = true
+	}
+	for _, name := range sortedKeys(names) {
+		fromDefinition, inFrom := fromDefinitions[name]
+		toDefinition, inTo := toDefinitions[name]
+		switch {
+		case !inFrom:
+			changelog.Definitions = append(changelog.Definitions, &DefinitionChange{Name: name, Kind: ChangeAdded})
+		case !inTo:
+			changelog.Definitions = append(changelog.Definitions, &DefinitionChange{Name: name, Kind: ChangeRemoved})
+		default:
+			if details := diffDefinition(fromDefinition, toDefinition); len(details) > 0 {
+				changelog.Definitions = append(changelog.Definitions, &DefinitionChange{Name: name, Kind: ChangeChanged, Details: details})
+			}
+		}
+	}
+
+	return changelog
+}
+
+func diffOperation(from, to *OperationObject) []string {
+	var details []string
+
+	if from.Deprecated != to.Deprecated {
+		if to.Deprecated {
+			details = append(details, "Deprecated")
+		} else {
+			details = append(details, "No longer deprecated")
+		}
+	}
+	if from.Summary != to.Summary {
+		details = append(details, fmt.Sprintf("Summary changed to %q", to.Summary))
+	}
+	if !JsonEqual(from.Consumes, to.Consumes) {
+		details = append(details, fmt.Sprintf("Consumes changed from %v to %v", from.Consumes, to.Consumes))
+	}
+	if !JsonEqual(from.Produces, to.Produces) {
+		details = append(details, fmt.Sprintf("Produces changed from %v to %v", from.Produces, to.Produces))
+	}
+
+	fromParameters := parametersByKey(from.Parameters)
+	toParameters := parametersByKey(to.Parameters)
+	keys := map[string]bool{}
+	for key, _ := range fromParameters {
+		keys[key] = true
+	}
+	for key, _ := range toParameters {
+		keys[key] = true
+	}
+	for _, key := range sortedKeys(keys) {
+		fromParameter, inFrom := fromParameters[key]
+		toParameter, inTo := toParameters[key]
+		switch {
+		case !inFrom:
+			details = append(details, fmt.Sprintf("Added %s parameter", key))
+		case !inTo:
+			details = append(details, fmt.Sprintf("Removed %s parameter", key))
+		case !JsonEqual(fromParameter, toParameter):
+			details = append(details, fmt.Sprintf("Changed %s parameter", key))
+		}
+	}
+
+	codes := map[string]bool{}
+	for code, _ := range from.Responses {
+		codes[code] = true
+	}
+	for code, _ := range to.Responses {
+		codes[code] = true
+	}
+	for _, code := range sortedKeys(codes) {
+		fromResponse, inFrom := from.Responses[code]
+		toResponse, inTo := to.Responses[code]
+		switch {
+		case !inFrom:
+			details = append(details, fmt.Sprintf("Added %s response", code))
+		case !inTo:
+			details = append(details, fmt.Sprintf("Removed %s response", code))
+		case !JsonEqual(fromResponse, toResponse):
+			details = append(details, fmt.Sprintf("Changed %s response", code))
+		}
+	}
+
+	return details
+}
+
+func diffDefinition(from, to *SchemaObject) []string {
+	var details []string
+
+	names := map[string]bool{}
+	for name, _ := range from.Properties {
+		names[name] = true
+	}
+	for name, _ := range to.Properties {
+		names[name] = true
+	}
+	for _, name := range sortedKeys(names) {
+		fromProperty, inFrom := from.Properties[name]
+		toProperty, inTo := to.Properties[name]
+		switch {
+		case !inFrom:
+			details = append(details, fmt.Sprintf("Added property %q", name))
+		case !inTo:
+			details = append(details, fmt.Sprintf("Removed property %q", name))
+		case !JsonEqual(fromProperty, toProperty):
+			details = append(details, fmt.Sprintf("Changed property %q", name))
+		}
+	}
+
+	for _, name := range to.Required {
+		if !IsInStringList(from.Required, name) {
+			details = append(details, fmt.Sprintf("Property %q is now required", name))
+		}
+	}
+	for _, name := range from.Required {
+		if !IsInStringList(to.Required, name) {
+			details = append(details, fmt.Sprintf("Property %q is no longer required", name))
+		}
+	}
+
+	if len(details) == 0 && !JsonEqual(from, to) {
+		details = append(details, "Schema changed")
+	}
+
+	return details
+}
+
+// String renders the changelog grouped by tag and operation.
+func (changelog *Changelog) String() string {
+	var buf bytes.Buffer
+
+	if changelog.From != "" || changelog.To != "" {
+		fmt.Fprintf(&buf, "# API changes from %s to %s\n", changelog.From, changelog.To)
+	} else {
+		fmt.Fprintf(&buf, "# API changes\n")
+	}
+	if len(changelog.Operations) == 0 && len(changelog.Definitions) == 0 {
+		fmt.Fprintf(&buf, "\nNo changes.\n")
+		return buf.String()
+	}
+
+	var tags []string
+	operationsByTag := map[string][]*OperationChange{}
+	for _, change := range changelog.Operations {
+		if _, ok := operationsByTag[change.Tag]; !ok {
+			tags = append(tags, change.Tag)
+		}
+		operationsByTag[change.Tag] = append(operationsByTag[change.Tag], change)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		fmt.Fprintf(&buf, "\n## %s\n\n", tag)
+		for _, change := range operationsByTag[tag] {
+			fmt.Fprintf(&buf, "- %s %s %s\n", change.Kind, change.Method, change.Path)
+			for _, detail := range change.Details {
+				fmt.Fprintf(&buf, "  - %s\n", detail)
+			}
+		}
+	}
+
+	if len(changelog.Definitions) > 0 {
+		fmt.Fprintf(&buf, "\n## Definitions\n\n")
+		for _, change := range changelog.Definitions {
+			fmt.Fprintf(&buf, "- %s %s\n", change.Kind, change.Name)
+			for _, detail := range change.Details {
+				fmt.Fprintf(&buf, "  - %s\n", detail)
+			}
+		}
+	}
+
+	return buf.String()
+}
+
+func swaggerOperations(swagger *SwaggerObject) map[string]*OperationObject {
+	operations := map[string]*OperationObject{}
+	if swagger == nil {
+		return operations
+	}
+	for path, item := range swagger.Paths {
+		for method, operation := range item.Operations() {
+			operations[method+" "+path] = operation
+		}
+	}
+	return operations
+}
+
+func splitOperationKey(key string) (string, string) {
+	parts := strings.SplitN(key, " ", 2)
+	return parts[0], parts[1]
+}
+
+// sortedOperationKeys sorts by path first and then by the order of Methods.
+func sortedOperationKeys(operationMaps ...map[string]*OperationObject) []string {
+	var keys []string
+	exists := map[string]bool{}
+	for _, operations := range operationMaps {
+		for key, _ := range operations {
+			if !exists[key] {
+				exists[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	methodIndex := map[string]int{}
+	for i, method := range Methods {
+		methodIndex[method] = i
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		methodI, pathI := splitOperationKey(keys[i])
+		methodJ, pathJ := splitOperationKey(keys[j])
+		if pathI != pathJ {
+			return pathI < pathJ
+		}
+		return methodIndex[methodI] < methodIndex[methodJ]
+	})
+	return keys
+}
+
+func operationTags(operation *OperationObject) []string {
+	if len(operation.Tags) == 0 {
+		return []string{"others"}
+	}
+	return operation.Tags
+}
+
+// parametersByKey keys parameters by "in name", e.g. `query "page"`.
+func parametersByKey(parameters []*ParameterObject) map[string]*ParameterObject {
+	result := map[string]*ParameterObject{}
+	for _, parameter := range parameters {
+		if parameter.Ref != "" {
+			result[fmt.Sprintf("%q", parameter.Ref)] = parameter
+		} else {
+			result[fmt.Sprintf("%s %q", parameter.In, parameter.Name)] = parameter
+		}
+	}
+	return result
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key, _ := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
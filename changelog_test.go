@@ -0,0 +1,99 @@
+package mswagger
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiffSwagger(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		expected string
+	}{
+		{
+			name:     "no changes",
+			from:     `{"paths": {"/users": {"get": {"tags": ["users"], "responses": {"200": {"description": "OK"}}}}}}`,
+			to:       `{"paths": {"/users": {"get": {"tags": ["users"], "responses": {"200": {"description": "OK"}}}}}}`,
+			expected: "# API changes\n\nNo changes.\n",
+		},
+		{
+			name: "added and removed operations",
+			from: `{"paths": {
+			  "/users": {"get": {"tags": ["users"], "responses": {"200": {"description": "OK"}}}},
+			  "/teams": {"get": {"responses": {"200": {"description": "OK"}}}}}}`,
+			to: `{"paths": {
+			  "/users": {"get": {"tags": ["users"], "responses": {"200": {"description": "OK"}}},
+			    "post": {"tags": ["users", "admin"], "responses": {"201": {"description": "Created"}}}}}}`,
+			expected: "# API changes\n" +
+				"\n## admin\n\n" +
+				"- Added POST /users\n" +
+				"\n## others\n\n" +
+				"- Removed GET /teams\n" +
+				"\n## users\n\n" +
+				"- Added POST /users\n",
+		},
+		{
+			name: "changed operation",
+			from: `{"paths": {"/users/{id}": {"get": {"tags": ["users"], "summary": "Get a user",
+			  "parameters": [
+			    {"name": "id", "in": "path", "required": true, "type": "string"},
+			    {"name": "fields", "in": "query", "type": "string"}],
+			  "responses": {"200": {"description": "OK"}, "404": {"description": "Not found"}}}}}}`,
+			to: `{"paths": {"/users/{id}": {"get": {"tags": ["users"], "summary": "Get the user", "deprecated": true,
+			  "parameters": [
+			    {"name": "id", "in": "path", "required": true, "type": "integer"},
+			    {"name": "expand", "in": "query", "type": "boolean"}],
+			  "responses": {"200": {"description": "OK"}, "403": {"description": "Forbidden"}}}}}}`,
+			expected: "# API changes\n" +
+				"\n## users\n\n" +
+				"- Changed GET /users/{id}\n" +
+				"  - Deprecated\n" +
+				"  - Summary changed to \"Get the user\"\n" +
+				"  - Changed path \"id\" parameter\n" +
+				"  - Added query \"expand\" parameter\n" +
+				"  - Removed query \"fields\" parameter\n" +
+				"  - Added 403 response\n" +
+				"  - Removed 404 response\n",
+		},
+		{
+			name: "definitions",
+			from: `{"paths": {}, "definitions": {
+			  "Team": {"type": "object"},
+			  "User": {"type": "object", "required": ["id"], "properties": {"id": {"type": "string"}, "age": {"type": "integer"}}},
+			  "Role": {"type": "string", "enum": ["admin"]}}}`,
+			to: `{"paths": {}, "definitions": {
+			  "User": {"type": "object", "required": ["name"], "properties": {"id": {"type": "integer"}, "name": {"type": "string"}}},
+			  "Role": {"type": "string", "enum": ["admin", "member"]},
+			  "Group": {"type": "object"}}}`,
+			expected: "# API changes\n" +
+				"\n## Definitions\n\n" +
+				"- Added Group\n" +
+				"- Changed Role\n" +
+				"  - Schema changed\n" +
+				"- Removed Team\n" +
+				"- Changed User\n" +
+				"  - Removed property \"age\"\n" +
+				"  - Changed property \"id\"\n" +
+				"  - Added property \"name\"\n" +
+				"  - Property \"name\" is now required\n" +
+				"  - Property \"id\" is no longer required\n",
+		},
+	}
+
+	for _, test := range tests {
+		from := &SwaggerObject{}
+		if err := json.Unmarshal([]byte(test.from), from); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		to := &SwaggerObject{}
+		if err := json.Unmarshal([]byte(test.to), to); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		actual := DiffSwagger(from, to).String()
+		if actual != test.expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", test.name, test.expected, actual)
+		}
+	}
+}
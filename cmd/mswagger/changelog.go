@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/mikunalpha/mswagger"
+)
+
+func init() {
+	commands["changelog"] = &command{
+		usage: "print the api changes between two git revisions",
+		run:   runChangelog,
+	}
+}
+
+func runChangelog(args []string) error {
+	var params mswagger.Params
+	var from, to string
+	flags := flag.NewFlagSet("changelog", flag.ExitOnError)
+	flags.StringVar(&params.ApiPackage, "apiPackage", "", "package of the api controllers")
+	flags.StringVar(&params.MainApiFile, "mainApiFile", "", "file with the general api info, relative to $GOPATH/src")
+	flags.StringVar(&params.ControllerClass, "controllerClass", "", "regular expression matching receivers of controllers")
+	flags.StringVar(&params.Ignore, "ignore", "", "regular expression of packages to ignore")
+	flags.BoolVar(&params.InferRoutes, "inferRoutes", false, "infer the path and method of controllers without @Router from route registrations")
+	flags.BoolVar(&params.InferHandlers, "inferHandlers", false, "infer request bodies, parameters and responses of controllers from their code")
+	flags.BoolVar(&params.EmbeddedAllOf, "embeddedAllOf", false, "render embedded structs as allOf of their definitions instead of copying their properties")
+	flags.BoolVar(&params.OmitemptyPolicy, "omitemptyPolicy", false, "require the fields without omitempty and make pointer fields nullable")
+	flags.StringVar(&from, "from", "", "old git revision")
+	flags.StringVar(&to, "to", "HEAD", "new git revision")
+	flags.Parse(args)
+
+	if from == "" {
+		return errors.New("-from was required.")
+	}
+
+	changelog, err := mswagger.GenerateChangelog(params, from, to)
+	if err != nil {
+		return err
+	}
+	fmt.Print(changelog)
+	return nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/mikunalpha/mswagger"
+)
+
+func init() {
+	commands["generate"] = &command{
+		usage: "generate swagger.json of an api package",
+		run:   runGenerate,
+	}
+}
+
+func runGenerate(args []string) error {
+	var params mswagger.Params
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	flags.StringVar(&params.ApiPackage, "apiPackage", "", "package of the api controllers")
+	flags.StringVar(&params.MainApiFile, "mainApiFile", "", "file with the general api info, relative to $GOPATH/src")
+	flags.StringVar(&params.OutputPath, "output", "swagger.json", "path of the generated file")
+	flags.StringVar(&params.ControllerClass, "controllerClass", "", "regular expression matching receivers of controllers")
+	flags.StringVar(&params.Ignore, "ignore", "", "regular expression of packages to ignore")
+	flags.BoolVar(&params.InferRoutes, "inferRoutes", false, "infer the path and method of controllers without @Router from route registrations")
+	flags.BoolVar(&params.InferHandlers, "inferHandlers", false, "infer request bodies, parameters and responses of controllers from their code")
+	flags.BoolVar(&params.EmbeddedAllOf, "embeddedAllOf", false, "render embedded structs as allOf of their definitions instead of copying their properties")
+	flags.BoolVar(&params.OmitemptyPolicy, "omitemptyPolicy", false, "require the fields without omitempty and make pointer fields nullable")
+	flags.StringVar(&params.Overlays, "overlays", "", "comma separated overlay or JSON merge patch files applied to the output")
+	flags.BoolVar(&params.Validate, "validate", false, "validate the output against the swagger 2.0 schema")
+	flags.BoolVar(&params.OpenAPI3, "openapi3", false, "write an OpenAPI 3.0 document converted from the swagger 2.0 one")
+	flags.Parse(args)
+
+	return mswagger.Run(params)
+}
@@ -0,0 +1,59 @@
+// Command mswagger generates and inspects swagger 2.0 documents of annotated api packages.
+//
+// Usage:
+//
+//	mswagger <command> [arguments]
+//
+// The commands are:
+//
+//	generate   generate swagger.json of an api package
+//	changelog  print the api changes between two git revisions
+//	merge      merge the swagger.json files of several services
+//	validate   validate swagger.json files
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+)
+
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]*command{}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "mswagger: unknown command %q\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd.run(flag.Args()[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "mswagger %s: %v\n", flag.Arg(0), err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: mswagger <command> [arguments]\n\nThe commands are:\n\n")
+	var names []string
+	for name, _ := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "\t%-10s %s\n", name, commands[name].usage)
+	}
+}
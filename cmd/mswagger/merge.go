@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mikunalpha/mswagger"
+)
+
+func init() {
+	commands["merge"] = &command{
+		usage: "merge the swagger.json files of several services",
+		run:   runMerge,
+	}
+}
+
+func runMerge(args []string) error {
+	var output, title, version, description string
+	var allowConflicts bool
+	flags := flag.NewFlagSet("merge", flag.ExitOnError)
+	flags.StringVar(&output, "output", "swagger.json", "path of the merged file")
+	flags.StringVar(&title, "title", "", "title of the merged document, defaults to the title of the first service")
+	flags.StringVar(&version, "version", "", "version of the merged document")
+	flags.StringVar(&description, "description", "", "description of the merged document")
+	flags.BoolVar(&allowConflicts, "allowConflicts", false, "write the merged file even if there are conflicts")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: mswagger merge [flags] [name[:prefix]=]swagger.json...\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		return errors.New("no swagger.json files given.")
+	}
+
+	var services []*mswagger.MergeService
+	for _, arg := range flags.Args() {
+		service := &mswagger.MergeService{}
+		filePath := arg
+		if i := strings.Index(arg, "="); i != -1 {
+			filePath = arg[i+1:]
+			service.Name = arg[:i]
+			if j := strings.Index(service.Name, ":"); j != -1 {
+				service.PathPrefix = service.Name[j+1:]
+				service.Name = service.Name[:j]
+			}
+		}
+		if service.Name == "" {
+			service.Name = filepath.Base(filepath.Dir(filePath))
+		}
+
+		swagger, err := mswagger.LoadSwagger(filePath)
+		if err != nil {
+			return err
+		}
+		service.Swagger = swagger
+		services = append(services, service)
+	}
+
+	merged, conflicts := mswagger.MergeSwagger(services)
+	for _, conflict := range conflicts {
+		fmt.Fprintf(os.Stderr, "conflict: %v\n", conflict)
+	}
+	if len(conflicts) > 0 && !allowConflicts {
+		return fmt.Errorf("%d conflicts found.", len(conflicts))
+	}
+
+	if title != "" {
+		merged.Info.Title = title
+	}
+	if version != "" {
+		merged.Info.Version = version
+	}
+	if description != "" {
+		merged.Info.Description = description
+	}
+
+	return mswagger.WriteSwagger(merged, output)
+}
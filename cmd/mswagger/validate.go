@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/mikunalpha/mswagger"
+)
+
+func init() {
+	commands["validate"] = &command{
+		usage: "validate swagger.json files",
+		run:   runValidate,
+	}
+}
+
+func runValidate(args []string) error {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		return errors.New("no swagger.json files given.")
+	}
+
+	invalid := 0
+	for _, filePath := range flags.Args() {
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		errs, err := mswagger.ValidateSwaggerJson(data)
+		if err != nil {
+			return fmt.Errorf("Can not parse %s: %v", filePath, err)
+		}
+		for _, e := range errs {
+			fmt.Printf("%s: %v\n", filePath, e)
+		}
+		if len(errs) > 0 {
+			invalid++
+		}
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d files are invalid.", invalid, flags.NArg())
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package mswagger
+
+import (
+	"fmt"
+	"go/ast"
+	"log"
+	"sort"
+	"strings"
+)
+
+// parseInterface turns an interface annotated with
+//
+//	// @Discriminator kind
+//	// @Subtype created CreatedEvent
+//	// @Subtype deleted DeletedEvent
+//	type Event interface{}
+//
+// into a model with the discriminator property kind and returns the models of
+// the subtypes. Without @Subtype the structs of the package implementing the
+// methods of the interface are the subtypes, with their names as values.
+func (m *Model) parseInterface(astTypeSpec *ast.TypeSpec, astInterfaceType *ast.InterfaceType, modelPackage string, knownModelNames map[string]bool) (error, []*Model) {
+	discriminator, subtypes := parseDiscriminatorComments(astTypeSpec.Doc)
+	if discriminator == "" {
+		return nil, nil
+	}
+	if len(subtypes) == 0 {
+		subtypes = m.parser.findImplementations(astInterfaceType, modelPackage)
+		if len(subtypes) == 0 {
+			log.Printf("Can not find implementations of %s in %s.\n", astTypeSpec.Name.Name, modelPackage)
+		}
+	}
+
+	m.discriminator = discriminator
+	m.Properties = map[string]*ModelProperty{}
+	m.setProperty(discriminator, &ModelProperty{Type: "string"})
+	m.Required = []string{discriminator}
+
+	var innerModels []*Model
+	for _, subtype := range subtypes {
+		subtypeModel := NewModel(m.parser)
+		err, subtypeInnerModels := subtypeModel.ParseModel(subtype[1], modelPackage, knownModelNames)
+		if err != nil {
+			return err, nil
+		}
+		if subtypeModel.Properties == nil {
+			return fmt.Errorf("Subtype %s of %s is not a struct.", subtype[1], astTypeSpec.Name.Name), nil
+		}
+		subtypeModel.parent = m.Id
+		subtypeModel.discriminatorValue = subtype[0]
+		innerModels = append(append(innerModels, subtypeModel), subtypeInnerModels...)
+	}
+	return nil, innerModels
+}
+
+// parseDiscriminatorComments returns the discriminator property and the [value, type name]
+// pairs of the subtypes of @Discriminator and @Subtype comments.
+func parseDiscriminatorComments(doc *ast.CommentGroup) (string, [][2]string) {
+	if doc == nil {
+		return "", nil
+	}
+	var discriminator string
+	var subtypes [][2]string
+	for _, comment := range doc.List {
+		fields := strings.Fields(strings.TrimSpace(strings.TrimLeft(comment.Text, "/")))
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToLower(fields[0]) {
+		case "@discriminator":
+			if len(fields) > 1 {
+				discriminator = fields[1]
+			}
+		case "@subtype":
+			if len(fields) > 2 {
+				subtypes = append(subtypes, [2]string{fields[1], fields[2]})
+			} else {
+				log.Printf("Can not parse subtype comment \"%s\", skipped.\n", comment.Text)
+			}
+		}
+	}
+	return discriminator, subtypes
+}
+
+// findImplementations returns the structs of packageName which have methods
+// named like those of astInterfaceType, with their names as values.
+func (parser *Parser) findImplementations(astInterfaceType *ast.InterfaceType, packageName string) [][2]string {
+	var methods []string
+	for _, method := range astInterfaceType.Methods.List {
+		for _, name := range method.Names {
+			methods = append(methods, name.Name)
+		}
+	}
+	if len(methods) == 0 {
+		// Every struct implements an empty interface
+		return nil
+	}
+
+	pkgRealPath := parser.GetRealPackagePath(packageName)
+	receiverMethods := parser.receiverMethods(packageName)
+
+	var names []string
+	for name, typeSpec := range parser.TypeDefinitions[pkgRealPath] {
+		if _, ok := typeSpec.Type.(*ast.StructType); !ok {
+			continue
+		}
+		implements := true
+		for _, method := range methods {
+			if !receiverMethods[name][method] {
+				implements = false
+				break
+			}
+		}
+		if implements {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var subtypes [][2]string
+	for _, name := range names {
+		subtypes = append(subtypes, [2]string{name, name})
+	}
+	return subtypes
+}
+
+// receiverMethods returns the names of the methods of the types of packageName.
+func (parser *Parser) receiverMethods(packageName string) map[string]map[string]bool {
+	if receiverMethods, ok := parser.receiverMethodsCache[packageName]; ok {
+		return receiverMethods
+	}
+	receiverMethods := map[string]map[string]bool{}
+	for _, astPackage := range parser.GetPackageAst(parser.GetRealPackagePath(packageName)) {
+		for _, astFile := range astPackage.Files {
+			for _, astDeclaration := range astFile.Decls {
+				if funcDeclaration, ok := astDeclaration.(*ast.FuncDecl); ok && funcDeclaration.Recv != nil && len(funcDeclaration.Recv.List) > 0 {
+					recv := receiverTypeName(funcDeclaration.Recv.List[0].Type)
+					if receiverMethods[recv] == nil {
+						receiverMethods[recv] = map[string]bool{}
+					}
+					receiverMethods[recv][funcDeclaration.Name.Name] = true
+				}
+			}
+		}
+	}
+	parser.receiverMethodsCache[packageName] = receiverMethods
+	return receiverMethods
+}
@@ -0,0 +1,389 @@
+package mswagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// The objects of the model keep their "x-" properties in Extensions.
+// Each of them marshals through a plain copy of its type, which has no
+// methods, and then appends or extracts the extensions.
+
+func (swagger SwaggerObject) MarshalJSON() ([]byte, error) {
+	type plain SwaggerObject
+	data, err := marshalExtensible(plain(swagger), swagger.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	return injectJsonFields(data, "paths", swagger.PathsExtensions)
+}
+
+func (swagger *SwaggerObject) UnmarshalJSON(data []byte) error {
+	type plain SwaggerObject
+	data, pathsExtensions, err := splitJsonExtensions(data, "paths")
+	if err != nil {
+		return err
+	}
+	swagger.Extensions, err = unmarshalExtensible(data, (*plain)(swagger))
+	swagger.PathsExtensions = pathsExtensions
+	return err
+}
+
+func (info InfoObject) MarshalJSON() ([]byte, error) {
+	type plain InfoObject
+	return marshalExtensible(plain(info), info.Extensions)
+}
+
+func (info *InfoObject) UnmarshalJSON(data []byte) (err error) {
+	type plain InfoObject
+	info.Extensions, err = unmarshalExtensible(data, (*plain)(info))
+	return err
+}
+
+func (contact Contact) MarshalJSON() ([]byte, error) {
+	type plain Contact
+	return marshalExtensible(plain(contact), contact.Extensions)
+}
+
+func (contact *Contact) UnmarshalJSON(data []byte) (err error) {
+	type plain Contact
+	contact.Extensions, err = unmarshalExtensible(data, (*plain)(contact))
+	return err
+}
+
+func (license License) MarshalJSON() ([]byte, error) {
+	type plain License
+	return marshalExtensible(plain(license), license.Extensions)
+}
+
+func (license *License) UnmarshalJSON(data []byte) (err error) {
+	type plain License
+	license.Extensions, err = unmarshalExtensible(data, (*plain)(license))
+	return err
+}
+
+func (item PathItemObject) MarshalJSON() ([]byte, error) {
+	type plain PathItemObject
+	return marshalExtensible(plain(item), item.Extensions)
+}
+
+func (item *PathItemObject) UnmarshalJSON(data []byte) (err error) {
+	type plain PathItemObject
+	item.Extensions, err = unmarshalExtensible(data, (*plain)(item))
+	return err
+}
+
+func (parameter ParameterObject) MarshalJSON() ([]byte, error) {
+	type plain ParameterObject
+	return marshalExtensible(plain(parameter), parameter.Extensions)
+}
+
+func (parameter *ParameterObject) UnmarshalJSON(data []byte) (err error) {
+	type plain ParameterObject
+	parameter.Extensions, err = unmarshalExtensible(data, (*plain)(parameter))
+	return err
+}
+
+func (items ItemsObject) MarshalJSON() ([]byte, error) {
+	type plain ItemsObject
+	return marshalExtensible(plain(items), items.Extensions)
+}
+
+func (items *ItemsObject) UnmarshalJSON(data []byte) (err error) {
+	type plain ItemsObject
+	items.Extensions, err = unmarshalExtensible(data, (*plain)(items))
+	return err
+}
+
+func (operation OperationObject) MarshalJSON() ([]byte, error) {
+	type plain OperationObject
+	fields := Extensions{}
+	for key, value := range operation.Extensions {
+		fields[key] = value
+	}
+	// omitempty would drop the security overriding the top level one
+	if operation.Security != nil && len(operation.Security) == 0 {
+		fields["security"] = []SecurityRequirementObject{}
+	}
+	data, err := marshalExtensible(plain(operation), fields)
+	if err != nil {
+		return nil, err
+	}
+	return injectJsonFields(data, "responses", operation.ResponsesExtensions)
+}
+
+func (operation *OperationObject) UnmarshalJSON(data []byte) error {
+	type plain OperationObject
+	data, responsesExtensions, err := splitJsonExtensions(data, "responses")
+	if err != nil {
+		return err
+	}
+	operation.Extensions, err = unmarshalExtensible(data, (*plain)(operation))
+	operation.ResponsesExtensions = responsesExtensions
+	return err
+}
+
+func (response ResponseObject) MarshalJSON() ([]byte, error) {
+	// References have no description
+	if response.Ref != "" {
+		return json.Marshal(ReferenceObject{Ref: response.Ref})
+	}
+	type plain ResponseObject
+	return marshalExtensible(plain(response), response.Extensions)
+}
+
+func (response *ResponseObject) UnmarshalJSON(data []byte) (err error) {
+	type plain ResponseObject
+	response.Extensions, err = unmarshalExtensible(data, (*plain)(response))
+	return err
+}
+
+func (schema SchemaObject) MarshalJSON() ([]byte, error) {
+	type plain SchemaObject
+	return marshalExtensible(plain(schema), schema.Extensions)
+}
+
+func (schema *SchemaObject) UnmarshalJSON(data []byte) (err error) {
+	type plain SchemaObject
+	schema.Extensions, err = unmarshalExtensible(data, (*plain)(schema))
+	return err
+}
+
+func (s SchemaObjectOrArray) MarshalJSON() ([]byte, error) {
+	if s.Schemas != nil {
+		return json.Marshal(s.Schemas)
+	}
+	return json.Marshal(s.Schema)
+}
+
+func (s *SchemaObjectOrArray) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		s.Schema = nil
+		return json.Unmarshal(data, &s.Schemas)
+	}
+	s.Schemas = nil
+	s.Schema = &SchemaObject{}
+	return json.Unmarshal(data, s.Schema)
+}
+
+func (b BoolOrSchemaObject) MarshalJSON() ([]byte, error) {
+	if b.Schema != nil {
+		return json.Marshal(b.Schema)
+	}
+	return json.Marshal(b.Allows)
+}
+
+func (b *BoolOrSchemaObject) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &b.Allows); err == nil {
+		b.Schema = nil
+		return nil
+	}
+	b.Allows = true
+	b.Schema = &SchemaObject{}
+	return json.Unmarshal(data, b.Schema)
+}
+
+func (xml XMLObject) MarshalJSON() ([]byte, error) {
+	type plain XMLObject
+	return marshalExtensible(plain(xml), xml.Extensions)
+}
+
+func (xml *XMLObject) UnmarshalJSON(data []byte) (err error) {
+	type plain XMLObject
+	xml.Extensions, err = unmarshalExtensible(data, (*plain)(xml))
+	return err
+}
+
+func (header HeaderObject) MarshalJSON() ([]byte, error) {
+	type plain HeaderObject
+	return marshalExtensible(plain(header), header.Extensions)
+}
+
+func (header *HeaderObject) UnmarshalJSON(data []byte) (err error) {
+	type plain HeaderObject
+	header.Extensions, err = unmarshalExtensible(data, (*plain)(header))
+	return err
+}
+
+func (scheme SecuritySchemeObject) MarshalJSON() ([]byte, error) {
+	type plain SecuritySchemeObject
+	return marshalExtensible(plain(scheme), scheme.Extensions)
+}
+
+func (scheme *SecuritySchemeObject) UnmarshalJSON(data []byte) (err error) {
+	type plain SecuritySchemeObject
+	scheme.Extensions, err = unmarshalExtensible(data, (*plain)(scheme))
+	return err
+}
+
+func (tag TagObject) MarshalJSON() ([]byte, error) {
+	type plain TagObject
+	return marshalExtensible(plain(tag), tag.Extensions)
+}
+
+func (tag *TagObject) UnmarshalJSON(data []byte) (err error) {
+	type plain TagObject
+	tag.Extensions, err = unmarshalExtensible(data, (*plain)(tag))
+	return err
+}
+
+func (docs ExternalDocumentationObject) MarshalJSON() ([]byte, error) {
+	type plain ExternalDocumentationObject
+	return marshalExtensible(plain(docs), docs.Extensions)
+}
+
+func (docs *ExternalDocumentationObject) UnmarshalJSON(data []byte) (err error) {
+	type plain ExternalDocumentationObject
+	docs.Extensions, err = unmarshalExtensible(data, (*plain)(docs))
+	return err
+}
+
+func marshalExtensible(v interface{}, extensions Extensions) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return appendJsonFields(data, extensions)
+}
+
+// unmarshalExtensible decodes data into v and returns the "x-" properties of data.
+func unmarshalExtensible(data []byte, v interface{}) (Extensions, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	var extensions Extensions
+	for key, raw := range fields {
+		if !strings.HasPrefix(key, "x-") {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+		if extensions == nil {
+			extensions = Extensions{}
+		}
+		extensions[key] = value
+	}
+	return extensions, nil
+}
+
+// appendJsonFields appends fields, sorted by key, to the encoded object data.
+func appendJsonFields(data []byte, fields map[string]interface{}) ([]byte, error) {
+	if len(fields) == 0 {
+		return data, nil
+	}
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		data = []byte("{}")
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key, _ := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	body := bytes.TrimSpace(data)
+	body = bytes.TrimSpace(body[:len(body)-1])
+	var buf bytes.Buffer
+	buf.Write(body)
+	for i, key := range keys {
+		if i > 0 || len(body) > 1 {
+			buf.WriteByte(',')
+		}
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		encodedValue, err := json.Marshal(fields[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		buf.Write(encodedValue)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// injectJsonFields appends fields to the object stored under key in the encoded object data.
+func injectJsonFields(data []byte, key string, fields map[string]interface{}) ([]byte, error) {
+	if len(fields) == 0 {
+		return data, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, err
+		}
+		if token != key {
+			continue
+		}
+		end := int(decoder.InputOffset())
+		start := end - len(raw)
+		value, err := appendJsonFields(raw, fields)
+		if err != nil {
+			return nil, err
+		}
+		result := append([]byte{}, data[:start]...)
+		result = append(result, value...)
+		return append(result, data[end:]...), nil
+	}
+	return appendJsonFields(data, map[string]interface{}{key: fields})
+}
+
+// splitJsonExtensions removes the "x-" properties of the object stored under
+// key in the encoded object data and returns them.
+func splitJsonExtensions(data []byte, key string) ([]byte, Extensions, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, nil, err
+	}
+	var nested map[string]json.RawMessage
+	if raw, ok := fields[key]; !ok || json.Unmarshal(raw, &nested) != nil {
+		return data, nil, nil
+	}
+
+	var extensions Extensions
+	for nestedKey, raw := range nested {
+		if !strings.HasPrefix(nestedKey, "x-") {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, nil, err
+		}
+		if extensions == nil {
+			extensions = Extensions{}
+		}
+		extensions[nestedKey] = value
+		delete(nested, nestedKey)
+	}
+	if extensions == nil {
+		return data, nil, nil
+	}
+
+	raw, err := json.Marshal(nested)
+	if err != nil {
+		return nil, nil, err
+	}
+	fields[key] = raw
+	data, err = json.Marshal(fields)
+	return data, extensions, err
+}
@@ -0,0 +1,34 @@
+package mswagger
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSchemaObjectJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+	}{
+		{name: "items schema", schema: `{"type":"array","items":{"type":"string"}}`},
+		{name: "items tuple", schema: `{"type":"array","items":[{"type":"string"},{"$ref":"#/definitions/User"}]}`},
+		{name: "empty items tuple", schema: `{"type":"array","items":[]}`},
+		{name: "additional properties", schema: `{"type":"object","additionalProperties":false}`},
+		{name: "additional properties schema", schema: `{"type":"object","additionalProperties":{"type":"integer"}}`},
+		{name: "extensions", schema: `{"type":"string","x-nullable":true}`},
+	}
+
+	for _, test := range tests {
+		schema := &SchemaObject{}
+		if err := json.Unmarshal([]byte(test.schema), schema); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		actual, err := json.Marshal(schema)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if string(actual) != test.schema {
+			t.Errorf("%s: expected %s, got %s", test.name, test.schema, actual)
+		}
+	}
+}
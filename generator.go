@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
+	"io/ioutil"
 	"log"
 	"os"
 	"path"
@@ -39,56 +40,120 @@ func InitParser(controllerClass, ignore string) *Parser {
 	parser.IsController = IsController
 	parser.Ignore = ignore
 
-	parser.TypesImplementingMarshalInterface["NullString"] = "string"
-	parser.TypesImplementingMarshalInterface["NullInt64"] = "int"
-	parser.TypesImplementingMarshalInterface["NullFloat64"] = "float"
-	parser.TypesImplementingMarshalInterface["NullBool"] = "bool"
-
 	return parser
 }
 
 type Params struct {
 	ApiPackage, MainApiFile, OutputFormat, OutputPath, ControllerClass, Ignore string
+	// Comma separated overlay or JSON merge patch files applied before the output is written
+	Overlays string
+	// Infer the path and method of controllers without @Router from route registrations
+	InferRoutes bool
+	// Infer request bodies, parameters and responses of controllers from their code
+	InferHandlers bool
+	// Render embedded structs as allOf of their definitions instead of copying their properties
+	EmbeddedAllOf bool
+	// Require the fields without omitempty and make pointer fields nullable
+	OmitemptyPolicy bool
+	// Swagger types of go types, overriding or adding to the default mappings
+	TypeMappings TypeMappings
+	// GOPATH the packages are looked up in, $GOPATH if it is empty
+	Gopath string
+	// Validate the generated document against the swagger 2.0 schema
+	Validate bool
+	// Write an OpenAPI 3.0 document converted from the swagger 2.0 one
+	OpenAPI3 bool
 }
 
 func Run(params Params) error {
-	gopath := os.Getenv("GOPATH")
-	if gopath == "" {
-		return errors.New("$GOPATH environment variable is empty.")
+	if params.OutputPath == "" {
+		params.OutputPath = "swagger-ui/index.js" // folder path
 	}
 
-	var err error
+	parser, err := ParseSwagger(params)
+	if err != nil {
+		return err
+	}
+
+	if err := ApplyOverlayFiles(parser.Swagger, splitOverlays(params.Overlays)); err != nil {
+		return err
+	}
+
+	// output, err := json.MarshalIndent(parser.Swagger, "", "  ")
+	// fmt.Println(string(output))
+
+	if params.OpenAPI3 {
+		err = WriteOpenAPI3(parser.Swagger, params.OutputPath)
+	} else {
+		err = generateSwaggerUiFiles(parser, params.OutputPath)
+	}
+	if err != nil || !params.Validate {
+		return err
+	}
+
+	errs, err := ValidateSwagger(parser.Swagger)
+	if err != nil {
+		return err
+	}
+	for _, e := range errs {
+		log.Printf("%s: %v\n", params.OutputPath, e)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s is invalid, %d errors found.", params.OutputPath, len(errs))
+	}
+	return nil
+}
+
+// ParseSwagger parses the main api file and the api package described by params
+// and returns the parser holding the resulting Swagger object.
+func ParseSwagger(params Params) (_ *Parser, err error) {
+	defer recoverParseError(&err)
+
+	gopath := params.Gopath
+	if gopath == "" {
+		gopath = os.Getenv("GOPATH")
+	}
+	if gopath == "" {
+		return nil, errors.New("$GOPATH environment variable is empty.")
+	}
 
 	defaultParams := Params{
-		OutputFormat:    "swagger",             // Current only swagger
-		OutputPath:      "swagger-ui/index.js", // folder path
+		OutputFormat:    "swagger", // Current only swagger
 		ControllerClass: "",
 		Ignore:          "swagger",
 	}
 
 	if params.ApiPackage == "" {
-		return errors.New("ApiPackage was required.")
+		return nil, errors.New("ApiPackage was required.")
 
 	}
 	if params.MainApiFile == "" {
-		return errors.New("MainApiFile was required.")
+		return nil, errors.New("MainApiFile was required.")
 	}
 	// if params.OutputFormat == "" {
 	// 	params.OutputFormat = defaultParams.OutputFormat
 	// }
 	params.OutputFormat = defaultParams.OutputFormat
-	if params.OutputPath == "" {
-		params.OutputPath = defaultParams.OutputPath
-	}
 	if params.ControllerClass == "" {
 		params.ControllerClass = defaultParams.ControllerClass
 	}
 	if params.Ignore == "" {
 		params.Ignore = defaultParams.Ignore
 	}
+	if _, err := regexp.Compile(params.ControllerClass); err != nil {
+		return nil, fmt.Errorf("The -controllerClass argument is not a valid regular expression: %v", err)
+	}
 
 	parser := InitParser(params.ControllerClass, params.Ignore)
 	parser.ApiPackage = params.ApiPackage
+	parser.InferRoutes = params.InferRoutes
+	parser.InferHandlers = params.InferHandlers
+	parser.EmbeddedAllOf = params.EmbeddedAllOf
+	parser.OmitemptyPolicy = params.OmitemptyPolicy
+	parser.Gopath = gopath
+	for typeName, swaggerType := range params.TypeMappings {
+		parser.TypeMappings[typeName] = swaggerType
+	}
 	// Support gopaths with multiple directories
 	dirs := strings.Split(gopath, ":")
 	if runtime.GOOS == "windows" {
@@ -100,6 +165,7 @@ func Run(params Params) error {
 		if _, err := os.Stat(apifile); err == nil {
 			parser.ParseGeneralSwaggerInfo(apifile)
 			found = true
+			break
 		}
 	}
 	if found == false {
@@ -107,28 +173,41 @@ func Run(params Params) error {
 			parser.ParseGeneralSwaggerInfo(params.MainApiFile)
 		} else {
 			apifile := path.Join(gopath, "src", params.MainApiFile)
-			return fmt.Errorf("Could not find apifile %s to parse\n", apifile)
+			return nil, fmt.Errorf("Could not find apifile %s to parse\n", apifile)
 		}
 	}
 
 	parser.ParseApi(params.ApiPackage)
 
-	// output, err := json.MarshalIndent(parser.Swagger, "", "  ")
-	// fmt.Println(string(output))
+	return parser, nil
+}
 
-	err = generateSwaggerUiFiles(parser, params.OutputPath)
+func generateSwaggerUiFiles(parser *Parser, OutputPath string) error {
+	return WriteSwagger(parser.Swagger, OutputPath)
+}
 
-	return err
+// LoadSwagger reads a swagger.json file.
+func LoadSwagger(filePath string) (*SwaggerObject, error) {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	swagger := &SwaggerObject{}
+	if err := json.Unmarshal(data, swagger); err != nil {
+		return nil, fmt.Errorf("Can not parse %s: %v", filePath, err)
+	}
+	return swagger, nil
 }
 
-func generateSwaggerUiFiles(parser *Parser, OutputPath string) error {
+// WriteSwagger writes swagger as indented json to OutputPath.
+func WriteSwagger(swagger *SwaggerObject, OutputPath string) error {
 	fd, err := os.Create(OutputPath)
 	if err != nil {
 		return fmt.Errorf("Can not create the master index.json file: %v\n", err)
 	}
 	defer fd.Close()
 
-	output, err := json.MarshalIndent(parser.Swagger, "", "  ")
+	output, err := json.MarshalIndent(swagger, "", "  ")
 	if err != nil {
 		return err
 	}
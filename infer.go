@@ -0,0 +1,602 @@
+package mswagger
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"log"
+	"sort"
+	"strconv"
+)
+
+// Diagnostic is a contradiction between the annotations of a controller and its code.
+type Diagnostic struct {
+	Package  string
+	Function string
+	Message  string
+}
+
+func (d *Diagnostic) String() string {
+	return fmt.Sprintf("%s.%s: %s", d.Package, d.Function, d.Message)
+}
+
+// handlerScope holds the types of the variables of a controller.
+type handlerScope struct {
+	operation *OperationObject
+	funcDecl  *ast.FuncDecl
+	types     map[string]ast.Expr
+	// import paths of the file of the controller by their names
+	imports map[string]string
+}
+
+// Methods of gin, echo and chi render which bind the request body
+var bodyBindMethods = map[string]bool{
+	"Bind":           true,
+	"BindJSON":       true,
+	"BindXML":        true,
+	"BindYAML":       true,
+	"BindWith":       true,
+	"MustBindWith":   true,
+	"ShouldBind":     true,
+	"ShouldBindJSON": true,
+	"ShouldBindXML":  true,
+	"ShouldBindYAML": true,
+	"ShouldBindWith": true,
+}
+
+// InferFromHandler completes operation with what the code of the controller funcDecl does.
+// imports are the import paths of the file of funcDecl by their names.
+func (operation *OperationObject) InferFromHandler(funcDecl *ast.FuncDecl, imports map[string]string) {
+	if funcDecl.Body == nil {
+		return
+	}
+	scope := &handlerScope{
+		operation: operation,
+		funcDecl:  funcDecl,
+		types:     map[string]ast.Expr{},
+		imports:   imports,
+	}
+	scope.addFieldTypes(funcDecl.Recv)
+	scope.addFieldTypes(funcDecl.Type.Params)
+
+	var bodyTypes []string
+	ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.AssignStmt:
+			if len(node.Lhs) == len(node.Rhs) {
+				for i, lhs := range node.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok && node.Tok == token.DEFINE {
+						if t := valueType(node.Rhs[i]); t != nil {
+							scope.types[ident.Name] = t
+						}
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for i, ident := range node.Names {
+				if node.Type != nil {
+					scope.types[ident.Name] = node.Type
+				} else if i < len(node.Values) {
+					if t := valueType(node.Values[i]); t != nil {
+						scope.types[ident.Name] = t
+					}
+				}
+			}
+		case *ast.CallExpr:
+			if typeName := scope.bodyType(node); typeName != "" && !IsInStringList(bodyTypes, typeName) {
+				bodyTypes = append(bodyTypes, typeName)
+			}
+		}
+		return true
+	})
+
+	for _, typeName := range bodyTypes {
+		operation.inferBody(funcDecl, typeName)
+	}
+	operation.inferParameters(funcDecl, scope.parameters())
+	knownDefinitions := operation.definitionNames()
+	operation.inferResponses(funcDecl, scope.responses(), knownDefinitions)
+}
+
+// bodyType returns the type name of the value the request body is decoded into by call.
+func (scope *handlerScope) bodyType(call *ast.CallExpr) string {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) == 0 {
+		return ""
+	}
+
+	var target ast.Expr
+	switch {
+	case selector.Sel.Name == "Decode":
+		// json.NewDecoder(r.Body).Decode(&req)
+		if decoder, ok := selector.X.(*ast.CallExpr); ok {
+			if fun, ok := decoder.Fun.(*ast.SelectorExpr); ok && fun.Sel.Name == "NewDecoder" && len(decoder.Args) == 1 && isRequestBody(decoder.Args[0]) {
+				target = call.Args[0]
+			}
+		}
+	case selector.Sel.Name == "DecodeJSON" || selector.Sel.Name == "DecodeXML":
+		// render.DecodeJSON(r.Body, &req)
+		if len(call.Args) == 2 && isRequestBody(call.Args[0]) {
+			target = call.Args[1]
+		}
+	case bodyBindMethods[selector.Sel.Name]:
+		// c.ShouldBindJSON(&req), c.Bind(&req) and render.Bind(r, &req)
+		target = call.Args[0]
+		if len(call.Args) == 2 && selector.Sel.Name == "Bind" {
+			target = call.Args[1]
+		}
+	}
+	if target == nil {
+		return ""
+	}
+
+	switch target := target.(type) {
+	case *ast.UnaryExpr:
+		if ident, ok := target.X.(*ast.Ident); ok && target.Op == token.AND {
+			return typeExprString(scope.types[ident.Name])
+		}
+	case *ast.Ident:
+		// req := &Request{}
+		if t, ok := scope.types[target.Name].(*ast.StarExpr); ok {
+			return typeExprString(t.X)
+		}
+	}
+	return ""
+}
+
+// inferBody registers typeName as the body parameter, unless it is documented otherwise.
+func (operation *OperationObject) inferBody(funcDecl *ast.FuncDecl, typeName string) {
+	for _, method := range operation.methods() {
+		if method == "GET" || method == "HEAD" {
+			// gin and echo Bind read the query of GET requests
+			return
+		}
+	}
+
+	// Definitions only needed for the comparison with annotations are dropped again
+	knownDefinitions := operation.definitionNames()
+	schema, err := operation.typeSchema(typeName)
+	if err != nil {
+		log.Printf("Can not infer the body of function: %v, package: %v, got error: %v\n", funcDecl.Name.Name, operation.packageName, err)
+		return
+	}
+
+	for _, parameter := range operation.Parameters {
+		switch parameter.In {
+		case "body":
+			if !JsonEqual(parameter.Schema, schema) {
+				operation.diagnose(funcDecl, "@Param %s body does not match %s decoded from the request body", parameter.Name, typeName)
+				operation.dropDefinitions(knownDefinitions)
+			}
+			return
+		case "formData":
+			operation.diagnose(funcDecl, "@Param %s formData contradicts %s decoded from the request body", parameter.Name, typeName)
+			operation.dropDefinitions(knownDefinitions)
+			return
+		}
+	}
+
+	operation.Parameters = append(operation.Parameters, &ParameterObject{
+		Name:     "body",
+		In:       "body",
+		Required: true,
+		Schema:   schema,
+	})
+}
+
+func (operation *OperationObject) definitionNames() map[string]bool {
+	names := map[string]bool{}
+	for k, _ := range operation.parser.Swagger.Definitions {
+		names[k] = true
+	}
+	return names
+}
+
+// dropDefinitions removes the definitions registered after names were taken.
+func (operation *OperationObject) dropDefinitions(names map[string]bool) {
+	for k, _ := range operation.parser.Swagger.Definitions {
+		if !names[k] {
+			delete(operation.parser.Swagger.Definitions, k)
+		}
+	}
+}
+
+// methods returns the http methods operation is routed with.
+func (operation *OperationObject) methods() []string {
+	var methods []string
+	for _, item := range operation.parser.Swagger.Paths {
+		for method, op := range item.Operations() {
+			if op == operation {
+				methods = append(methods, method)
+			}
+		}
+	}
+	return methods
+}
+
+func (operation *OperationObject) diagnose(funcDecl *ast.FuncDecl, format string, args ...interface{}) {
+	diagnostic := &Diagnostic{
+		Package:  operation.packageName,
+		Function: funcDecl.Name.Name,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	operation.parser.Diagnostics = append(operation.parser.Diagnostics, diagnostic)
+	log.Println(diagnostic)
+}
+
+func (scope *handlerScope) addFieldTypes(fields *ast.FieldList) {
+	if fields == nil {
+		return
+	}
+	for _, field := range fields.List {
+		for _, ident := range field.Names {
+			scope.types[ident.Name] = field.Type
+		}
+	}
+}
+
+// valueType returns the type of composite literals like T{}, &T{} and new(T).
+func valueType(expr ast.Expr) ast.Expr {
+	switch expr := expr.(type) {
+	case *ast.UnaryExpr:
+		if expr.Op == token.AND {
+			if t := valueType(expr.X); t != nil {
+				return &ast.StarExpr{X: t}
+			}
+		}
+	case *ast.CompositeLit:
+		return expr.Type
+	case *ast.CallExpr:
+		if ident, ok := expr.Fun.(*ast.Ident); ok && ident.Name == "new" && len(expr.Args) == 1 {
+			return &ast.StarExpr{X: expr.Args[0]}
+		}
+	}
+	return nil
+}
+
+// typeExprString returns the type name of expr the way annotations write it, like models.User.
+func typeExprString(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.StarExpr:
+		return typeExprString(expr.X)
+	case *ast.SelectorExpr:
+		if x := typeExprString(expr.X); x != "" {
+			return x + "." + expr.Sel.Name
+		}
+	case *ast.ArrayType:
+		if expr.Len == nil {
+			if elt := typeExprString(expr.Elt); elt != "" {
+				return "[]" + elt
+			}
+		}
+	}
+	return ""
+}
+
+// isRequestBody reports whether expr is r.Body or c.Request.Body.
+func isRequestBody(expr ast.Expr) bool {
+	selector, ok := expr.(*ast.SelectorExpr)
+	return ok && selector.Sel.Name == "Body"
+}
+
+// inferredResponse is a response written by a controller. Schema is nil for
+// responses without body, typeName is empty if the type of the body is unknown.
+type inferredResponse struct {
+	code     string
+	typeName string
+	schema   *SchemaObject
+}
+
+// Methods of gin and echo contexts writing a response, with the index of the body
+// argument, -1 for text bodies and 0 for responses without body
+var responseMethods = map[string]int{
+	"JSON":                 1,
+	"JSONP":                1,
+	"IndentedJSON":         1,
+	"SecureJSON":           1,
+	"PureJSON":             1,
+	"AsciiJSON":            1,
+	"JSONPretty":           1,
+	"XML":                  1,
+	"XMLPretty":            1,
+	"YAML":                 1,
+	"AbortWithStatusJSON":  1,
+	"String":               -1,
+	"HTML":                 -1,
+	"NoContent":            0,
+	"Status":               0,
+	"AbortWithStatus":      0,
+	"Redirect":             0,
+	"AbortWithError":       0,
+	"SecureJSONWithPrefix": 1,
+}
+
+// responses returns the responses written by the controller. Status codes set
+// by w.WriteHeader and render.Status apply to the following writes of the same block.
+func (scope *handlerScope) responses() []*inferredResponse {
+	var responses []*inferredResponse
+	add := func(code string, body ast.Expr) {
+		response := &inferredResponse{code: code, typeName: "-"}
+		if body != nil {
+			response.typeName, response.schema = scope.bodySchema(body)
+		}
+		responses = append(responses, response)
+	}
+
+	type entry struct {
+		node   ast.Node
+		status string
+	}
+	var stack []entry
+	status := "200"
+	ast.Inspect(scope.funcDecl.Body, func(node ast.Node) bool {
+		if node == nil {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			switch top.node.(type) {
+			case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause:
+				status = top.status
+			}
+			return true
+		}
+		stack = append(stack, entry{node, status})
+
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		name := selector.Sel.Name
+		switch {
+		case name == "WriteHeader" && len(call.Args) == 1:
+			if code, ok := statusCode(call.Args[0]); ok {
+				status = code
+			}
+		case name == "Status" && len(call.Args) == 2:
+			// render.Status(r, http.StatusCreated)
+			if code, ok := statusCode(call.Args[1]); ok {
+				status = code
+			}
+		case name == "Encode" && len(call.Args) == 1:
+			// json.NewEncoder(w).Encode(resp)
+			if encoder, ok := selector.X.(*ast.CallExpr); ok {
+				if fun, ok := encoder.Fun.(*ast.SelectorExpr); ok && fun.Sel.Name == "NewEncoder" {
+					add(status, call.Args[0])
+				}
+			}
+		case (name == "JSON" || name == "XML") && len(call.Args) == 3 && scope.isPackageCall(selector, "github.com/go-chi/render"):
+			// render.JSON(w, r, resp)
+			add(status, call.Args[2])
+		case name == "NoContent" && len(call.Args) == 2 && scope.isPackageCall(selector, "github.com/go-chi/render"):
+			// render.NoContent(w, r)
+			add("204", nil)
+		case name == "Error" && len(call.Args) == 3 && scope.isPackageCall(selector, "net/http"):
+			// http.Error(w, "message", http.StatusBadRequest)
+			if code, ok := statusCode(call.Args[2]); ok {
+				add(code, &ast.BasicLit{Kind: token.STRING})
+			}
+		default:
+			// c.JSON(http.StatusOK, resp) of gin and echo
+			index, ok := responseMethods[name]
+			if !ok || len(call.Args) == 0 || scope.isPackageCall(selector) {
+				return true
+			}
+			code, ok := statusCode(call.Args[0])
+			if !ok {
+				return true
+			}
+			switch {
+			case index > 0 && index < len(call.Args):
+				add(code, call.Args[index])
+			case index < 0:
+				add(code, &ast.BasicLit{Kind: token.STRING})
+			default:
+				add(code, nil)
+			}
+		}
+		return true
+	})
+	return responses
+}
+
+// bodySchema returns the type name and schema of a response body, the type name is
+// empty when the type is unknown.
+func (scope *handlerScope) bodySchema(body ast.Expr) (string, *SchemaObject) {
+	var t ast.Expr
+	switch body := body.(type) {
+	case *ast.BasicLit:
+		if body.Kind == token.STRING {
+			return "string", &SchemaObject{Type: "string"}
+		}
+	case *ast.Ident:
+		t = scope.types[body.Name]
+	case *ast.UnaryExpr, *ast.CompositeLit, *ast.CallExpr:
+		t = valueType(body)
+	}
+
+	switch mapType := t.(type) {
+	case *ast.MapType:
+		return "map", &SchemaObject{Type: "object"}
+	case *ast.SelectorExpr:
+		// gin.H and echo.Map
+		if mapType.Sel.Name == "H" || mapType.Sel.Name == "Map" {
+			return "map", &SchemaObject{Type: "object"}
+		}
+	}
+
+	typeName := typeExprString(t)
+	if typeName == "" && t != nil && hasAnonymousStruct(t) {
+		schema, err := scope.operation.anonymousSchema(t)
+		if err != nil {
+			log.Printf("Can not infer the response of function: %v, package: %v, got error: %v\n", scope.funcDecl.Name.Name, scope.operation.packageName, err)
+			return "", nil
+		}
+		return "struct", schema
+	}
+	if typeName == "" {
+		return "", nil
+	}
+	schema, err := scope.operation.typeSchema(typeName)
+	if err != nil {
+		log.Printf("Can not infer the response of function: %v, package: %v, got error: %v\n", scope.funcDecl.Name.Name, scope.operation.packageName, err)
+		return "", nil
+	}
+	return typeName, schema
+}
+
+// inferResponses adds the responses written by the controller funcDecl and reports those
+// contradicting the annotations.
+func (operation *OperationObject) inferResponses(funcDecl *ast.FuncDecl, responses []*inferredResponse, knownDefinitions map[string]bool) {
+	if len(responses) == 0 {
+		// The responses are written somewhere else
+		return
+	}
+
+	produced := map[string]bool{}
+	inferred := map[string]bool{}
+	for _, response := range responses {
+		produced[response.code] = true
+		if inferred[response.code] {
+			continue
+		}
+
+		documented, ok := operation.Responses[response.code]
+		if !ok {
+			inferred[response.code] = true
+			operation.diagnose(funcDecl, "response %s is produced by the code but not documented", response.code)
+			code, _ := strconv.Atoi(response.code)
+			operation.Responses[response.code] = &ResponseObject{
+				Description: statusDescription(code),
+				Schema:      response.schema,
+			}
+			continue
+		}
+		if response.typeName != "" && response.typeName != "map" && !JsonEqual(documentedSchema(documented), response.schema) {
+			typeName := response.typeName
+			if typeName == "-" {
+				typeName = "no body"
+			}
+			operation.diagnose(funcDecl, "response %s documents another schema than %s produced by the code", response.code, typeName)
+		}
+	}
+
+	var codes []string
+	for code, _ := range operation.Responses {
+		if !produced[code] && code != "default" {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		operation.diagnose(funcDecl, "response %s is documented but never produced by the code", code)
+	}
+
+	if len(inferred) == 0 {
+		// Definitions were only registered for the comparison with annotations
+		operation.dropDefinitions(knownDefinitions)
+	}
+}
+
+// documentedSchema returns the schema of an annotated response, nil if it has no body.
+func documentedSchema(response *ResponseObject) *SchemaObject {
+	if response.Schema == nil || JsonEqual(response.Schema, &SchemaObject{}) {
+		return nil
+	}
+	return response.Schema
+}
+
+// statusCode returns the status code of 201 or http.StatusCreated.
+func statusCode(expr ast.Expr) (string, bool) {
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		if expr.Kind == token.INT {
+			return expr.Value, true
+		}
+	case *ast.SelectorExpr:
+		if code, ok := httpStatusCodes[expr.Sel.Name]; ok {
+			return strconv.Itoa(code), true
+		}
+	}
+	return "", false
+}
+
+// isPackageCall reports whether selector calls a function of a package imported by the
+// file of the controller, like render.JSON. With importPaths the package must be one of them.
+func (scope *handlerScope) isPackageCall(selector *ast.SelectorExpr, importPaths ...string) bool {
+	ident, ok := selector.X.(*ast.Ident)
+	if !ok || scope.types[ident.Name] != nil {
+		return false
+	}
+	importPath, ok := scope.imports[ident.Name]
+	return ok && (len(importPaths) == 0 || IsInStringList(importPaths, importPath))
+}
+
+var httpStatusCodes = map[string]int{
+	"StatusContinue":                      100,
+	"StatusSwitchingProtocols":            101,
+	"StatusProcessing":                    102,
+	"StatusEarlyHints":                    103,
+	"StatusOK":                            200,
+	"StatusCreated":                       201,
+	"StatusAccepted":                      202,
+	"StatusNonAuthoritativeInfo":          203,
+	"StatusNoContent":                     204,
+	"StatusResetContent":                  205,
+	"StatusPartialContent":                206,
+	"StatusMultiStatus":                   207,
+	"StatusAlreadyReported":               208,
+	"StatusIMUsed":                        226,
+	"StatusMultipleChoices":               300,
+	"StatusMovedPermanently":              301,
+	"StatusFound":                         302,
+	"StatusSeeOther":                      303,
+	"StatusNotModified":                   304,
+	"StatusUseProxy":                      305,
+	"StatusTemporaryRedirect":             307,
+	"StatusPermanentRedirect":             308,
+	"StatusBadRequest":                    400,
+	"StatusUnauthorized":                  401,
+	"StatusPaymentRequired":               402,
+	"StatusForbidden":                     403,
+	"StatusNotFound":                      404,
+	"StatusMethodNotAllowed":              405,
+	"StatusNotAcceptable":                 406,
+	"StatusProxyAuthRequired":             407,
+	"StatusRequestTimeout":                408,
+	"StatusConflict":                      409,
+	"StatusGone":                          410,
+	"StatusLengthRequired":                411,
+	"StatusPreconditionFailed":            412,
+	"StatusRequestEntityTooLarge":         413,
+	"StatusRequestURITooLong":             414,
+	"StatusUnsupportedMediaType":          415,
+	"StatusRequestedRangeNotSatisfiable":  416,
+	"StatusExpectationFailed":             417,
+	"StatusTeapot":                        418,
+	"StatusMisdirectedRequest":            421,
+	"StatusUnprocessableEntity":           422,
+	"StatusLocked":                        423,
+	"StatusFailedDependency":              424,
+	"StatusTooEarly":                      425,
+	"StatusUpgradeRequired":               426,
+	"StatusPreconditionRequired":          428,
+	"StatusTooManyRequests":               429,
+	"StatusRequestHeaderFieldsTooLarge":   431,
+	"StatusUnavailableForLegalReasons":    451,
+	"StatusInternalServerError":           500,
+	"StatusNotImplemented":                501,
+	"StatusBadGateway":                    502,
+	"StatusServiceUnavailable":            503,
+	"StatusGatewayTimeout":                504,
+	"StatusHTTPVersionNotSupported":       505,
+	"StatusVariantAlsoNegotiates":         506,
+	"StatusInsufficientStorage":           507,
+	"StatusLoopDetected":                  508,
+	"StatusNotExtended":                   510,
+	"StatusNetworkAuthenticationRequired": 511,
+}
@@ -0,0 +1,359 @@
+package mswagger
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// parameterReads collects the parameters a controller reads from the request.
+type parameterReads struct {
+	scope      *handlerScope
+	parameters []*ParameterObject
+	// parameters assigned to variables, like page := c.Query("page")
+	variables map[string]*ParameterObject
+	// variables holding r.URL.Query() and mux.Vars(r)
+	queryValues map[string]bool
+	pathVars    map[string]bool
+}
+
+// Functions of strconv and the go types of the values they return
+var strconvTypes = map[string]string{
+	"Atoi":       "int",
+	"ParseInt":   "int64",
+	"ParseUint":  "uint64",
+	"ParseFloat": "float64",
+	"ParseBool":  "bool",
+}
+
+// parameters returns the query, header, path and form parameters read by the controller.
+func (scope *handlerScope) parameters() []*ParameterObject {
+	reads := &parameterReads{
+		scope:       scope,
+		variables:   map[string]*ParameterObject{},
+		queryValues: map[string]bool{},
+		pathVars:    map[string]bool{},
+	}
+
+	ast.Inspect(scope.funcDecl.Body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range node.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok || i >= len(node.Rhs) {
+					continue
+				}
+				switch {
+				case reads.scope.isURLQuery(node.Rhs[i]):
+					// q := r.URL.Query()
+					reads.queryValues[ident.Name] = true
+				case reads.scope.isMuxVars(node.Rhs[i]):
+					// vars := mux.Vars(r)
+					reads.pathVars[ident.Name] = true
+				default:
+					if parameter := reads.read(node.Rhs[i]); parameter != nil {
+						reads.variables[ident.Name] = parameter
+					}
+				}
+			}
+			// page, ok := c.GetQuery("page")
+			if len(node.Rhs) == 1 && len(node.Lhs) > 1 {
+				if ident, ok := node.Lhs[0].(*ast.Ident); ok {
+					if parameter := reads.read(node.Rhs[0]); parameter != nil {
+						reads.variables[ident.Name] = parameter
+					}
+				}
+			}
+		case *ast.CallExpr:
+			if selector, ok := node.Fun.(*ast.SelectorExpr); ok && len(node.Args) > 0 {
+				if pkg, ok := selector.X.(*ast.Ident); ok && pkg.Name == "strconv" {
+					if goType, ok := strconvTypes[selector.Sel.Name]; ok {
+						if parameter := reads.read(node.Args[0]); parameter != nil {
+							reads.setType(parameter, conversionType(goType, node.Args))
+						}
+					}
+				}
+			}
+			reads.read(node)
+		case *ast.IndexExpr:
+			reads.read(node)
+		}
+		return true
+	})
+
+	for _, parameter := range reads.parameters {
+		if value, ok := parameter.Default.(string); ok {
+			parameter.Default = parseDefault(parameter.Type, value)
+		}
+	}
+	return reads.parameters
+}
+
+// read returns the parameter expr reads from the request, nil if it does not read one.
+func (reads *parameterReads) read(expr ast.Expr) *ParameterObject {
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return reads.read(expr.X)
+	case *ast.Ident:
+		return reads.variables[expr.Name]
+	case *ast.IndexExpr:
+		// mux.Vars(r)["id"] and vars["id"]
+		name, ok := stringValue(expr.Index)
+		if !ok {
+			return nil
+		}
+		if ident, ok := expr.X.(*ast.Ident); (ok && reads.pathVars[ident.Name]) || reads.scope.isMuxVars(expr.X) {
+			return reads.add(name, "path", "string", nil)
+		}
+	case *ast.CallExpr:
+		selector, ok := expr.Fun.(*ast.SelectorExpr)
+		if !ok || len(expr.Args) == 0 {
+			return nil
+		}
+		name, ok := stringValue(expr.Args[0])
+		if !ok {
+			// chi.URLParam(r, "id")
+			if selector.Sel.Name != "URLParam" || len(expr.Args) != 2 || !reads.scope.isRequest(expr.Args[0]) {
+				return nil
+			}
+			if name, ok = stringValue(expr.Args[1]); !ok {
+				return nil
+			}
+		} else if selector.Sel.Name != "Get" && !reads.scope.isRequest(selector.X) {
+			// Like db.Query("SELECT ..."), only the request and the context are read
+			return nil
+		}
+
+		var defaultValue interface{}
+		if len(expr.Args) == 2 {
+			if value, ok := stringValue(expr.Args[1]); ok {
+				defaultValue = value
+			}
+		}
+
+		switch selector.Sel.Name {
+		case "Get":
+			if ident, ok := selector.X.(*ast.Ident); (ok && reads.queryValues[ident.Name]) || reads.scope.isURLQuery(selector.X) {
+				// r.URL.Query().Get("page")
+				return reads.add(name, "query", "string", nil)
+			}
+			if header, ok := selector.X.(*ast.SelectorExpr); ok && header.Sel.Name == "Header" && reads.scope.isRequest(header.X) {
+				// r.Header.Get("X-Tenant")
+				return reads.add(name, "header", "string", nil)
+			}
+		case "Query", "GetQuery", "QueryParam":
+			return reads.add(name, "query", "string", nil)
+		case "DefaultQuery":
+			return reads.add(name, "query", "string", defaultValue)
+		case "QueryArray", "GetQueryArray":
+			return reads.add(name, "query", "[]string", nil)
+		case "Param":
+			return reads.add(name, "path", "string", nil)
+		case "URLParam":
+			return reads.add(name, "path", "string", nil)
+		case "GetHeader":
+			return reads.add(name, "header", "string", nil)
+		case "PostForm", "GetPostForm", "PostFormValue":
+			return reads.add(name, "formData", "string", nil)
+		case "DefaultPostForm":
+			return reads.add(name, "formData", "string", defaultValue)
+		case "PostFormArray", "GetPostFormArray":
+			return reads.add(name, "formData", "[]string", nil)
+		case "FormFile":
+			// r.FormFile("avatar") and c.FormFile("avatar")
+			return reads.add(name, "formData", "file", nil)
+		case "FormValue":
+			// r.FormValue reads the query and the form
+			for _, method := range reads.scope.operation.methods() {
+				if method == "POST" || method == "PUT" || method == "PATCH" {
+					return reads.add(name, "formData", "string", nil)
+				}
+			}
+			return reads.add(name, "query", "string", nil)
+		}
+	}
+	return nil
+}
+
+func (reads *parameterReads) add(name, in, goType string, defaultValue interface{}) *ParameterObject {
+	for _, parameter := range reads.parameters {
+		if parameter.Name == name && parameter.In == in {
+			if parameter.Default == nil {
+				parameter.Default = defaultValue
+			}
+			return parameter
+		}
+	}
+
+	parameter := &ParameterObject{
+		Name:     name,
+		In:       in,
+		Required: in == "path",
+		Default:  defaultValue,
+	}
+	reads.setType(parameter, goType)
+	if parameter.Type == "array" {
+		// c.QueryArray("tag") reads ?tag=a&tag=b
+		parameter.CollectionFormat = "multi"
+	}
+	reads.parameters = append(reads.parameters, parameter)
+	return parameter
+}
+
+// inferParameters adds the parameters read by the controller funcDecl which are not documented.
+func (operation *OperationObject) inferParameters(funcDecl *ast.FuncDecl, parameters []*ParameterObject) {
+	for _, parameter := range parameters {
+		documented := false
+		for _, explicit := range operation.Parameters {
+			if !parameterNameEqual(explicit, parameter) {
+				continue
+			}
+			if explicit.In != parameter.In {
+				operation.diagnose(funcDecl, "parameter %s is read from %s but documented in %s", parameter.Name, parameter.In, explicit.In)
+			}
+			documented = true
+		}
+		if documented {
+			continue
+		}
+
+		if parameter.In == "path" && !operation.hasPathParameter(parameter.Name) {
+			operation.diagnose(funcDecl, "path parameter %s is read but not part of the route", parameter.Name)
+			continue
+		}
+		operation.diagnose(funcDecl, "%s parameter %s is read but not documented", parameter.In, parameter.Name)
+		operation.Parameters = append(operation.Parameters, parameter)
+	}
+}
+
+// hasPathParameter reports whether the paths of operation contain {name}.
+func (operation *OperationObject) hasPathParameter(name string) bool {
+	for itemPath, item := range operation.parser.Swagger.Paths {
+		for _, op := range item.Operations() {
+			if op == operation && strings.Contains(itemPath, "{"+name+"}") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func parameterNameEqual(a, b *ParameterObject) bool {
+	if a.In == "header" || b.In == "header" {
+		// Header names are case insensitive
+		return strings.EqualFold(a.Name, b.Name)
+	}
+	return a.Name == b.Name
+}
+
+// setType sets the swagger type of the go type a parameter is read or converted as.
+func (reads *parameterReads) setType(parameter *ParameterObject, goType string) {
+	mappings := reads.scope.operation.parser.TypeMappings
+	if strings.HasPrefix(goType, "[]") {
+		parameter.Type = "array"
+		parameter.Format = ""
+		parameter.Minimum = nil
+		parameter.Items = &ItemsObject{
+			Type:    mappings.Type(goType[2:]),
+			Format:  mappings.Format(goType[2:]),
+			Minimum: mappings.Minimum(goType[2:]),
+		}
+		return
+	}
+	if parameter.Type == "array" {
+		// strconv.Atoi(c.QueryArray("id")[0]) does not change the type of the array
+		return
+	}
+	parameter.Type = mappings.Type(goType)
+	parameter.Format = mappings.Format(goType)
+	parameter.Minimum = mappings.Minimum(goType)
+}
+
+// conversionType returns the go type of strconv.ParseInt(s, 10, 32) and the like.
+func conversionType(goType string, args []ast.Expr) string {
+	bitSize := ""
+	switch goType {
+	case "int64", "uint64":
+		if len(args) == 3 {
+			if literal, ok := args[2].(*ast.BasicLit); ok && literal.Kind == token.INT {
+				bitSize = literal.Value
+			}
+		}
+	case "float64":
+		if len(args) == 2 {
+			if literal, ok := args[1].(*ast.BasicLit); ok && literal.Kind == token.INT {
+				bitSize = literal.Value
+			}
+		}
+	}
+	switch bitSize {
+	case "8", "16", "32":
+		return strings.TrimSuffix(goType, "64") + bitSize
+	}
+	return goType
+}
+
+// parseDefault converts the default value of a parameter to its type.
+func parseDefault(parameterType, value string) interface{} {
+	switch parameterType {
+	case "integer":
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+// isCallOf reports whether expr calls a function or method name with argc arguments.
+// Types of the request and context parameters of controllers
+var requestTypes = map[string]bool{
+	"http.Request": true,
+	"gin.Context":  true,
+	"echo.Context": true,
+}
+
+// isRequest reports whether expr is the request or context of the controller, like r,
+// c and c.Request.
+func (scope *handlerScope) isRequest(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return scope.isRequest(expr.X)
+	case *ast.Ident:
+		return requestTypes[typeExprString(scope.types[expr.Name])]
+	case *ast.SelectorExpr:
+		return expr.Sel.Name == "Request" && scope.isRequest(expr.X)
+	}
+	return false
+}
+
+// isURLQuery reports whether expr is r.URL.Query() of the request r.
+func (scope *handlerScope) isURLQuery(expr ast.Expr) bool {
+	if !isCallOf(expr, "Query", 0) {
+		return false
+	}
+	url, ok := expr.(*ast.CallExpr).Fun.(*ast.SelectorExpr).X.(*ast.SelectorExpr)
+	return ok && url.Sel.Name == "URL" && scope.isRequest(url.X)
+}
+
+// isMuxVars reports whether expr is mux.Vars(r) of the request r.
+func (scope *handlerScope) isMuxVars(expr ast.Expr) bool {
+	return isCallOf(expr, "Vars", 1) && scope.isRequest(expr.(*ast.CallExpr).Args[0])
+}
+
+func isCallOf(expr ast.Expr, name string, argc int) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != argc {
+		return false
+	}
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && selector.Sel.Name == name
+}
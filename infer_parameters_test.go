@@ -0,0 +1,106 @@
+package mswagger
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"testing"
+)
+
+func parseHandler(t *testing.T, src string) *ast.FuncDecl {
+	file, err := goparser.ParseFile(token.NewFileSet(), "handler.go", "package handlers\n"+src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, decl := range file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+			return funcDecl
+		}
+	}
+	t.Fatal("No function declared.")
+	return nil
+}
+
+func TestParametersReadFromRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected []string
+	}{
+		{
+			name: "database queries are not parameters",
+			src: `func (h *Users) List(w http.ResponseWriter, r *http.Request) {
+	rows, _ := h.db.Query("SELECT id FROM users")
+	h.db.QueryRow("SELECT count(*) FROM users")
+	page := r.URL.Query().Get("page")
+	_, _ = rows, page
+}`,
+			expected: []string{"query page"},
+		},
+		{
+			name: "query values of other urls are not parameters",
+			src: `func (h *Users) List(w http.ResponseWriter, r *http.Request) {
+	q := h.callback.Query()
+	_ = q.Get("token")
+	_ = r.Header.Get("X-Request-Id")
+	_ = h.headers.Get("X-Other")
+}`,
+			expected: []string{"header X-Request-Id"},
+		},
+		{
+			name: "gin context",
+			src: `func (h *Users) List(c *gin.Context) {
+	_ = c.Query("sort")
+	_ = c.Param("id")
+	_ = c.Request.FormValue("name")
+	_ = h.cache.Param("key")
+	_ = h.db.Query("SELECT 1")
+}`,
+			expected: []string{"query sort", "path id", "query name"},
+		},
+		{
+			name: "echo context",
+			src: `func (h *Users) List(c echo.Context) error {
+	_ = c.QueryParam("limit")
+	_ = h.store.QueryParam("limit2")
+	return nil
+}`,
+			expected: []string{"query limit"},
+		},
+		{
+			name: "path variables of the request",
+			src: `func (h *Users) Get(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	other := mux.Vars(h.last)
+	_ = vars["id"]
+	_ = other["name"]
+	_ = chi.URLParam(r, "slug")
+	_ = chi.URLParam(h.last, "key")
+}`,
+			expected: []string{"path id", "path slug"},
+		},
+	}
+
+	for _, test := range tests {
+		operation := NewOperationObject(NewParser(), "handlers")
+		funcDecl := parseHandler(t, test.src)
+		scope := &handlerScope{operation: operation, funcDecl: funcDecl, types: map[string]ast.Expr{}}
+		scope.addFieldTypes(funcDecl.Recv)
+		scope.addFieldTypes(funcDecl.Type.Params)
+
+		var actual []string
+		for _, parameter := range scope.parameters() {
+			actual = append(actual, parameter.In+" "+parameter.Name)
+		}
+		if len(actual) != len(test.expected) {
+			t.Errorf("%s: expected parameters %v, got %v", test.name, test.expected, actual)
+			continue
+		}
+		for i := range actual {
+			if actual[i] != test.expected[i] {
+				t.Errorf("%s: expected parameters %v, got %v", test.name, test.expected, actual)
+				break
+			}
+		}
+	}
+}
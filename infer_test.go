@@ -0,0 +1,210 @@
+package mswagger
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestResponsesWrittenByHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected []string
+	}{
+		{
+			name: "chi render",
+			src: `import "github.com/go-chi/render"
+
+func Create(w http.ResponseWriter, r *http.Request) {
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, map[string]string{})
+}`,
+			expected: []string{"201 map"},
+		},
+		{
+			name: "renamed chi render",
+			src: `import chirender "github.com/go-chi/render"
+
+func Delete(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "" {
+		chirender.JSON(w, r, "missing")
+		return
+	}
+	chirender.NoContent(w, r)
+}`,
+			expected: []string{"200 string", "204 -"},
+		},
+		{
+			name: "variable named like a package",
+			src: `func Get(w http.ResponseWriter, r *http.Request, render *Renderer) {
+	render.JSON(w, r, "user")
+}`,
+		},
+		{
+			name: "renamed net/http",
+			src: `import nethttp "net/http"
+
+func Get(w nethttp.ResponseWriter, r *nethttp.Request) {
+	nethttp.Error(w, "bad request", nethttp.StatusBadRequest)
+}`,
+			expected: []string{"400 string"},
+		},
+		{
+			name: "gin context and package functions",
+			src: `import (
+	"github.com/gin-gonic/gin"
+	"example.com/views"
+)
+
+func Get(c *gin.Context) {
+	views.HTML(http.StatusOK, "page")
+	c.JSON(http.StatusOK, gin.H{})
+}`,
+			expected: []string{"200 map"},
+		},
+	}
+
+	for _, test := range tests {
+		file, err := goparser.ParseFile(token.NewFileSet(), "handler.go", "package handlers\n"+test.src, 0)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		var funcDecl *ast.FuncDecl
+		for _, decl := range file.Decls {
+			if decl, ok := decl.(*ast.FuncDecl); ok {
+				funcDecl = decl
+			}
+		}
+		scope := &handlerScope{
+			operation: NewOperationObject(NewParser(), "handlers"),
+			funcDecl:  funcDecl,
+			types:     map[string]ast.Expr{},
+			imports:   fileImports(file),
+		}
+		scope.addFieldTypes(funcDecl.Type.Params)
+
+		var actual []string
+		for _, response := range scope.responses() {
+			actual = append(actual, response.code+" "+response.typeName)
+		}
+		if len(actual) != len(test.expected) {
+			t.Errorf("%s: expected responses %v, got %v", test.name, test.expected, actual)
+			continue
+		}
+		for i := range actual {
+			if actual[i] != test.expected[i] {
+				t.Errorf("%s: expected responses %v, got %v", test.name, test.expected, actual)
+				break
+			}
+		}
+	}
+}
+
+func TestInferFromHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		comments    []string
+		src         string
+		responses   []string
+		diagnostics []string
+	}{
+		{
+			name:     "documented responses",
+			comments: []string{`@Success 200 {string} string "OK"`, `@Failure 404 {string} string "Not found"`},
+			src: `func Get(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "" {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	render.JSON(w, r, "user")
+}`,
+			responses: []string{"200", "404"},
+		},
+		{
+			name:     "undocumented response",
+			comments: []string{`@Success 200 {string} string "OK"`},
+			src: `func Get(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "" {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	render.JSON(w, r, "user")
+}`,
+			responses:   []string{"200", "400"},
+			diagnostics: []string{"handlers.Get: response 400 is produced by the code but not documented"},
+		},
+		{
+			name:     "unproduced response",
+			comments: []string{`@Success 200 {string} string "OK"`, `@Failure 500 {string} string "Internal error"`},
+			src: `func Get(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, "user")
+}`,
+			responses:   []string{"200", "500"},
+			diagnostics: []string{"handlers.Get: response 500 is documented but never produced by the code"},
+		},
+		{
+			name:     "other schema",
+			comments: []string{`@Success 200 {string} string "OK"`, `@Success 204 {string} string "No Content"`},
+			src: `func Delete(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "" {
+		render.JSON(w, r, map[string]string{})
+		return
+	}
+	render.NoContent(w, r)
+}`,
+			responses:   []string{"200", "204"},
+			diagnostics: []string{"handlers.Delete: response 204 documents another schema than no body produced by the code"},
+		},
+		{
+			name:     "responses written somewhere else",
+			comments: []string{`@Success 200 {string} string "OK"`},
+			src: `func Get(w http.ResponseWriter, r *http.Request) {
+	writeUser(w, r)
+}`,
+			responses: []string{"200"},
+		},
+	}
+
+	for _, test := range tests {
+		file, err := goparser.ParseFile(token.NewFileSet(), "handler.go", "package handlers\n"+
+			"import (\n\t\"net/http\"\n\t\"github.com/go-chi/render\"\n)\n"+test.src, 0)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		var funcDecl *ast.FuncDecl
+		for _, decl := range file.Decls {
+			if decl, ok := decl.(*ast.FuncDecl); ok {
+				funcDecl = decl
+			}
+		}
+
+		parser := NewParser()
+		operation := NewOperationObject(parser, "handlers")
+		for _, comment := range test.comments {
+			if err := operation.ParseComment(comment); err != nil {
+				t.Fatalf("%s: %v", test.name, err)
+			}
+		}
+		operation.InferFromHandler(funcDecl, fileImports(file))
+
+		var responses []string
+		for code, _ := range operation.Responses {
+			responses = append(responses, code)
+		}
+		sort.Strings(responses)
+		if strings.Join(responses, ",") != strings.Join(test.responses, ",") {
+			t.Errorf("%s: expected responses %v, got %v", test.name, test.responses, responses)
+		}
+		var diagnostics []string
+		for _, diagnostic := range parser.Diagnostics {
+			diagnostics = append(diagnostics, diagnostic.String())
+		}
+		if strings.Join(diagnostics, "\n") != strings.Join(test.diagnostics, "\n") {
+			t.Errorf("%s: expected diagnostics %q, got %q", test.name, test.diagnostics, diagnostics)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package mswagger
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonNode is a value of a decoded json document and the keys leading to it,
+// strings for object properties and ints for array elements.
+type jsonNode struct {
+	path  []interface{}
+	value interface{}
+}
+
+type jsonPathSelector func(nodes []jsonNode) []jsonNode
+
+// JsonPathQuery returns the paths of the values of document selected by query.
+// document must be decoded by encoding/json into an interface{}.
+//
+// The supported syntax is a subset of JSONPath (RFC 9535): the root $, child
+// names .name and ['name'], indexes [0] and [-1], unions ['a','b'], wildcards
+// .* and [*], recursive descent ..name and filters like [?(@.in == 'query')]
+// using ==, != or the existence of a property.
+func JsonPathQuery(document interface{}, query string) ([][]interface{}, error) {
+	selectors, err := parseJsonPath(query)
+	if err != nil {
+		return nil, err
+	}
+	nodes := []jsonNode{{value: document}}
+	for _, selector := range selectors {
+		nodes = selector(nodes)
+	}
+	paths := make([][]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		paths = append(paths, node.path)
+	}
+	return paths, nil
+}
+
+func parseJsonPath(query string) ([]jsonPathSelector, error) {
+	query = strings.TrimSpace(query)
+	if !strings.HasPrefix(query, "$") {
+		return nil, fmt.Errorf("JSONPath %q must start with $", query)
+	}
+
+	var selectors []jsonPathSelector
+	rest := query[1:]
+	for len(rest) > 0 {
+		descendant := false
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			descendant = true
+			rest = rest[2:]
+		case rest[0] == '.':
+			rest = rest[1:]
+		case rest[0] == '[':
+		default:
+			return nil, fmt.Errorf("Can not parse JSONPath %q at %q", query, rest)
+		}
+		if descendant {
+			selectors = append(selectors, selectDescendants)
+		}
+
+		var selector jsonPathSelector
+		if strings.HasPrefix(rest, "[") {
+			end := bracketEnd(rest)
+			if end == -1 {
+				return nil, fmt.Errorf("Unterminated [ in JSONPath %q", query)
+			}
+			var err error
+			if selector, err = parseJsonPathBracket(rest[1:end]); err != nil {
+				return nil, fmt.Errorf("Can not parse JSONPath %q: %v", query, err)
+			}
+			rest = rest[end+1:]
+		} else {
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			if name == "" {
+				return nil, fmt.Errorf("Empty name in JSONPath %q", query)
+			}
+			if name == "*" {
+				selector = selectWildcard
+			} else {
+				selector = selectNames([]string{name})
+			}
+			rest = rest[end:]
+		}
+		selectors = append(selectors, selector)
+	}
+	return selectors, nil
+}
+
+// bracketEnd returns the index of the ] closing the [ at the start of s.
+func bracketEnd(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parseJsonPathBracket(content string) (jsonPathSelector, error) {
+	content = strings.TrimSpace(content)
+	if content == "*" {
+		return selectWildcard, nil
+	}
+	if strings.HasPrefix(content, "?") {
+		return parseJsonPathFilter(strings.TrimSpace(content[1:]))
+	}
+
+	var names []string
+	var indexes []int
+	for _, part := range splitOutsideQuotes(content, ',') {
+		part = strings.TrimSpace(part)
+		if literal, ok := parseQuoted(part); ok {
+			names = append(names, literal)
+		} else if index, err := strconv.Atoi(part); err == nil {
+			indexes = append(indexes, index)
+		} else {
+			return nil, fmt.Errorf("unsupported selector [%s]", content)
+		}
+	}
+	if len(indexes) > 0 && len(names) > 0 {
+		return nil, fmt.Errorf("mixed names and indexes in [%s]", content)
+	}
+	if len(indexes) > 0 {
+		return selectIndexes(indexes), nil
+	}
+	return selectNames(names), nil
+}
+
+func parseJsonPathFilter(filter string) (jsonPathSelector, error) {
+	if strings.HasPrefix(filter, "(") && strings.HasSuffix(filter, ")") {
+		filter = strings.TrimSpace(filter[1 : len(filter)-1])
+	}
+
+	operator := ""
+	i := indexOutsideQuotes(filter, "==", "!=")
+	if i != -1 {
+		operator = filter[i : i+2]
+	}
+
+	left := filter
+	var right interface{}
+	if operator != "" {
+		left = strings.TrimSpace(filter[:i])
+		rightText := strings.TrimSpace(filter[i+2:])
+		if literal, ok := parseQuoted(rightText); ok {
+			right = literal
+		} else if number, err := strconv.ParseFloat(rightText, 64); err == nil {
+			right = number
+		} else if rightText == "true" || rightText == "false" {
+			right = rightText == "true"
+		} else if rightText != "null" {
+			return nil, fmt.Errorf("unsupported filter value %s", rightText)
+		}
+	}
+	if !strings.HasPrefix(left, "@") {
+		return nil, fmt.Errorf("filter must start with @: %s", filter)
+	}
+	selectors, err := parseJsonPath("$" + left[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	return func(nodes []jsonNode) []jsonNode {
+		var result []jsonNode
+		for _, child := range selectWildcard(nodes) {
+			matches := []jsonNode{{value: child.value}}
+			for _, selector := range selectors {
+				matches = selector(matches)
+			}
+			switch operator {
+			case "":
+				if len(matches) > 0 {
+					result = append(result, child)
+				}
+			case "==", "!=":
+				equal := len(matches) > 0 && reflect.DeepEqual(matches[0].value, right)
+				if equal == (operator == "==") {
+					result = append(result, child)
+				}
+			}
+		}
+		return result
+	}, nil
+}
+
+func selectNames(names []string) jsonPathSelector {
+	return func(nodes []jsonNode) []jsonNode {
+		var result []jsonNode
+		for _, node := range nodes {
+			if object, ok := node.value.(map[string]interface{}); ok {
+				for _, name := range names {
+					if value, ok := object[name]; ok {
+						result = append(result, jsonNode{path: appendPath(node.path, name), value: value})
+					}
+				}
+			}
+		}
+		return result
+	}
+}
+
+func selectIndexes(indexes []int) jsonPathSelector {
+	return func(nodes []jsonNode) []jsonNode {
+		var result []jsonNode
+		for _, node := range nodes {
+			if array, ok := node.value.([]interface{}); ok {
+				for _, index := range indexes {
+					if index < 0 {
+						index += len(array)
+					}
+					if index >= 0 && index < len(array) {
+						result = append(result, jsonNode{path: appendPath(node.path, index), value: array[index]})
+					}
+				}
+			}
+		}
+		return result
+	}
+}
+
+func selectWildcard(nodes []jsonNode) []jsonNode {
+	var result []jsonNode
+	for _, node := range nodes {
+		switch value := node.value.(type) {
+		case map[string]interface{}:
+			keys := make([]string, 0, len(value))
+			for key, _ := range value {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				result = append(result, jsonNode{path: appendPath(node.path, key), value: value[key]})
+			}
+		case []interface{}:
+			for i, element := range value {
+				result = append(result, jsonNode{path: appendPath(node.path, i), value: element})
+			}
+		}
+	}
+	return result
+}
+
+// selectDescendants returns the nodes and all of their descendants.
+func selectDescendants(nodes []jsonNode) []jsonNode {
+	var result []jsonNode
+	for _, node := range nodes {
+		result = append(result, node)
+		result = append(result, selectDescendants(selectWildcard([]jsonNode{node}))...)
+	}
+	return result
+}
+
+func appendPath(path []interface{}, key interface{}) []interface{} {
+	result := make([]interface{}, len(path), len(path)+1)
+	copy(result, path)
+	return append(result, key)
+}
+
+func parseQuoted(s string) (string, bool) {
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return strings.Replace(s[1:len(s)-1], "\\"+string(s[0]), string(s[0]), -1), true
+	}
+	return "", false
+}
+
+// indexOutsideQuotes returns the index of the first of operators in s which is not
+// in a quoted string, or -1.
+func indexOutsideQuotes(s string, operators ...string) int {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		default:
+			for _, operator := range operators {
+				if strings.HasPrefix(s[i:], operator) {
+					return i
+				}
+			}
+		}
+	}
+	return -1
+}
+
+func splitOutsideQuotes(s string, separator byte) []string {
+	var parts []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == separator:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
@@ -0,0 +1,81 @@
+package mswagger
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJsonPathQuery(t *testing.T) {
+	document := `{
+	  "paths": {
+	    "/users": {
+	      "get": {"parameters": [
+	        {"name": "page", "in": "query", "required": false},
+	        {"name": "X-Tenant", "in": "header", "required": true},
+	        {"name": "a == b", "in": "query"}]},
+	      "post": {"parameters": [{"name": "user", "in": "body", "required": true}]}}},
+	  "tags": [{"name": "users"}, {"name": "admin", "x-internal": true}]
+	}`
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{query: "$.tags", expected: `[["tags"]]`},
+		{query: "$['paths']['/users'].get", expected: `[["paths", "/users", "get"]]`},
+		{query: "$.tags[0].name", expected: `[["tags", 0, "name"]]`},
+		{query: "$.tags[-1]", expected: `[["tags", 1]]`},
+		{query: "$.tags[5]", expected: `[]`},
+		{query: "$.tags[*].name", expected: `[["tags", 0, "name"], ["tags", 1, "name"]]`},
+		{query: "$.paths['/users']['get','post']", expected: `[["paths", "/users", "get"], ["paths", "/users", "post"]]`},
+		{query: "$.paths.*.post", expected: `[["paths", "/users", "post"]]`},
+		{query: "$..required", expected: `[
+		  ["paths", "/users", "get", "parameters", 0, "required"],
+		  ["paths", "/users", "get", "parameters", 1, "required"],
+		  ["paths", "/users", "post", "parameters", 0, "required"]]`},
+		{query: "$.paths..parameters[?(@.in == 'query')].name", expected: `[
+		  ["paths", "/users", "get", "parameters", 0, "name"],
+		  ["paths", "/users", "get", "parameters", 2, "name"]]`},
+		{query: "$.paths..parameters[?(@.in != 'query')].name", expected: `[
+		  ["paths", "/users", "get", "parameters", 1, "name"],
+		  ["paths", "/users", "post", "parameters", 0, "name"]]`},
+		{query: "$..parameters[?(@.required == true)].in", expected: `[
+		  ["paths", "/users", "get", "parameters", 1, "in"],
+		  ["paths", "/users", "post", "parameters", 0, "in"]]`},
+		{query: "$..parameters[?(@.name == 'a == b')]", expected: `[["paths", "/users", "get", "parameters", 2]]`},
+		{query: "$.tags[?(@['x-internal'])]", expected: `[["tags", 1]]`},
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(document), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		paths, err := JsonPathQuery(decoded, test.query)
+		if err != nil {
+			t.Errorf("%s: %v", test.query, err)
+			continue
+		}
+		var expected interface{}
+		if err := json.Unmarshal([]byte(test.expected), &expected); err != nil {
+			t.Fatalf("%s: %v", test.query, err)
+		}
+		if !JsonEqual(paths, expected) {
+			actual, _ := json.Marshal(paths)
+			t.Errorf("%s: expected %s, got %s", test.query, test.expected, actual)
+		}
+	}
+}
+
+func TestJsonPathQueryErrors(t *testing.T) {
+	tests := []string{
+		"paths",
+		"$.tags[?(@.name == users)]",
+		"$.tags[?(name == 'users')]",
+	}
+
+	for _, query := range tests {
+		if _, err := JsonPathQuery(map[string]interface{}{}, query); err == nil {
+			t.Errorf("%s: expected an error", query)
+		}
+	}
+}
@@ -0,0 +1,414 @@
+package mswagger
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// jsonSchemaValidator validates decoded json values against JSON Schema draft 4.
+// It supports the keywords used by the Swagger 2.0 schema; formats are not checked.
+type jsonSchemaValidator struct {
+	// documents maps schema ids without fragment to the decoded schema
+	documents map[string]interface{}
+	patterns  map[string]*regexp.Regexp
+}
+
+func newJsonSchemaValidator(schemas ...string) (*jsonSchemaValidator, error) {
+	validator := &jsonSchemaValidator{
+		documents: map[string]interface{}{},
+		patterns:  map[string]*regexp.Regexp{},
+	}
+	for _, schema := range schemas {
+		var document interface{}
+		if err := json.Unmarshal([]byte(schema), &document); err != nil {
+			return nil, err
+		}
+		id, _ := document.(map[string]interface{})["id"].(string)
+		validator.documents[strings.TrimSuffix(id, "#")] = document
+	}
+	return validator, nil
+}
+
+// Validate validates instance against the schema document with the given id.
+func (validator *jsonSchemaValidator) Validate(id string, instance interface{}) []*ValidationError {
+	document := validator.documents[strings.TrimSuffix(id, "#")]
+	return validator.validate(document, document, instance, "")
+}
+
+func (validator *jsonSchemaValidator) validate(document, schema, instance interface{}, pointer string) []*ValidationError {
+	schemaObject, ok := schema.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	if ref, ok := schemaObject["$ref"].(string); ok {
+		refDocument, refSchema, err := validator.resolve(document, ref)
+		if err != nil {
+			return []*ValidationError{{Path: pointer, Message: err.Error()}}
+		}
+		return validator.validate(refDocument, refSchema, instance, pointer)
+	}
+
+	var errs []*ValidationError
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, &ValidationError{Path: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if types, ok := schemaObject["type"]; ok && !matchesJsonType(types, instance) {
+		fail("must be of type %v, got %s", jsonTypeNames(types), jsonTypeOf(instance))
+		return errs
+	}
+	if enum, ok := schemaObject["enum"].([]interface{}); ok {
+		found := false
+		for _, value := range enum {
+			if reflect.DeepEqual(value, instance) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("must be one of %s, got %s", compactJson(enum), compactJson(instance))
+		}
+	}
+
+	switch value := instance.(type) {
+	case map[string]interface{}:
+		errs = append(errs, validator.validateObject(document, schemaObject, value, pointer)...)
+	case []interface{}:
+		errs = append(errs, validator.validateArray(document, schemaObject, value, pointer)...)
+	case string:
+		length := float64(utf8.RuneCountInString(value))
+		if max, ok := schemaObject["maxLength"].(float64); ok && length > max {
+			fail("must be at most %v characters long", max)
+		}
+		if min, ok := schemaObject["minLength"].(float64); ok && length < min {
+			fail("must be at least %v characters long", min)
+		}
+		if pattern, ok := schemaObject["pattern"].(string); ok {
+			if re := validator.pattern(pattern); re != nil && !re.MatchString(value) {
+				fail("must match the pattern %s", pattern)
+			}
+		}
+	case float64:
+		if max, ok := schemaObject["maximum"].(float64); ok {
+			if exclusive, _ := schemaObject["exclusiveMaximum"].(bool); exclusive && value >= max {
+				fail("must be less than %v", max)
+			} else if value > max {
+				fail("must be at most %v", max)
+			}
+		}
+		if min, ok := schemaObject["minimum"].(float64); ok {
+			if exclusive, _ := schemaObject["exclusiveMinimum"].(bool); exclusive && value <= min {
+				fail("must be greater than %v", min)
+			} else if value < min {
+				fail("must be at least %v", min)
+			}
+		}
+		if multipleOf, ok := schemaObject["multipleOf"].(float64); ok && multipleOf > 0 {
+			if quotient := value / multipleOf; quotient != math.Trunc(quotient) {
+				fail("must be a multiple of %v", multipleOf)
+			}
+		}
+	}
+
+	if allOf, ok := schemaObject["allOf"].([]interface{}); ok {
+		for _, subSchema := range allOf {
+			errs = append(errs, validator.validate(document, subSchema, instance, pointer)...)
+		}
+	}
+	if anyOf, ok := schemaObject["anyOf"].([]interface{}); ok {
+		var best []*ValidationError
+		for i, subSchema := range anyOf {
+			subErrs := validator.validate(document, subSchema, instance, pointer)
+			if len(subErrs) == 0 {
+				best = nil
+				break
+			}
+			if i == 0 || len(subErrs) < len(best) {
+				best = subErrs
+			}
+		}
+		errs = append(errs, best...)
+	}
+	if oneOf, ok := schemaObject["oneOf"].([]interface{}); ok {
+		matched := 0
+		var best []*ValidationError
+		for i, subSchema := range oneOf {
+			subErrs := validator.validate(document, subSchema, instance, pointer)
+			if len(subErrs) == 0 {
+				matched++
+			} else if i == 0 || best == nil || len(subErrs) < len(best) {
+				best = subErrs
+			}
+		}
+		if matched == 0 {
+			// Report the alternative which came closest
+			errs = append(errs, best...)
+		} else if matched > 1 {
+			fail("must match exactly one schema of oneOf, matched %d", matched)
+		}
+	}
+	if not, ok := schemaObject["not"]; ok {
+		if len(validator.validate(document, not, instance, pointer)) == 0 {
+			fail("must not match the schema %s", compactJson(not))
+		}
+	}
+
+	return errs
+}
+
+func (validator *jsonSchemaValidator) validateObject(document interface{}, schema map[string]interface{}, object map[string]interface{}, pointer string) []*ValidationError {
+	var errs []*ValidationError
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, &ValidationError{Path: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if _, ok := object[name.(string)]; !ok {
+				fail("missing required property %q", name)
+			}
+		}
+	}
+	if max, ok := schema["maxProperties"].(float64); ok && float64(len(object)) > max {
+		fail("must have at most %v properties", max)
+	}
+	if min, ok := schema["minProperties"].(float64); ok && float64(len(object)) < min {
+		fail("must have at least %v properties", min)
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	patternProperties, _ := schema["patternProperties"].(map[string]interface{})
+	additionalProperties, hasAdditionalProperties := schema["additionalProperties"]
+
+	keys := make([]string, 0, len(object))
+	for key, _ := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := object[key]
+		childPointer := pointer + "/" + escapeJsonPointer(key)
+		matched := false
+		if propertySchema, ok := properties[key]; ok {
+			matched = true
+			errs = append(errs, validator.validate(document, propertySchema, value, childPointer)...)
+		}
+		for pattern, patternSchema := range patternProperties {
+			if re := validator.pattern(pattern); re != nil && re.MatchString(key) {
+				matched = true
+				errs = append(errs, validator.validate(document, patternSchema, value, childPointer)...)
+			}
+		}
+		if matched || !hasAdditionalProperties {
+			continue
+		}
+		switch additional := additionalProperties.(type) {
+		case bool:
+			if !additional {
+				errs = append(errs, &ValidationError{Path: childPointer, Message: "property is not allowed"})
+			}
+		case map[string]interface{}:
+			errs = append(errs, validator.validate(document, additional, value, childPointer)...)
+		}
+	}
+
+	if dependencies, ok := schema["dependencies"].(map[string]interface{}); ok {
+		for name, dependency := range dependencies {
+			if _, ok := object[name]; !ok {
+				continue
+			}
+			switch dependency := dependency.(type) {
+			case []interface{}:
+				for _, required := range dependency {
+					if _, ok := object[required.(string)]; !ok {
+						fail("property %q requires property %q", name, required)
+					}
+				}
+			case map[string]interface{}:
+				errs = append(errs, validator.validate(document, dependency, object, pointer)...)
+			}
+		}
+	}
+
+	return errs
+}
+
+func (validator *jsonSchemaValidator) validateArray(document interface{}, schema map[string]interface{}, array []interface{}, pointer string) []*ValidationError {
+	var errs []*ValidationError
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, &ValidationError{Path: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if max, ok := schema["maxItems"].(float64); ok && float64(len(array)) > max {
+		fail("must have at most %v items", max)
+	}
+	if min, ok := schema["minItems"].(float64); ok && float64(len(array)) < min {
+		fail("must have at least %v items", min)
+	}
+	if unique, _ := schema["uniqueItems"].(bool); unique {
+	unique:
+		for i := range array {
+			for j := 0; j < i; j++ {
+				if reflect.DeepEqual(array[i], array[j]) {
+					fail("items %d and %d must be unique", j, i)
+					break unique
+				}
+			}
+		}
+	}
+
+	switch items := schema["items"].(type) {
+	case map[string]interface{}:
+		for i, item := range array {
+			errs = append(errs, validator.validate(document, items, item, pointer+"/"+strconv.Itoa(i))...)
+		}
+	case []interface{}:
+		for i, item := range array {
+			childPointer := pointer + "/" + strconv.Itoa(i)
+			if i < len(items) {
+				errs = append(errs, validator.validate(document, items[i], item, childPointer)...)
+				continue
+			}
+			switch additional := schema["additionalItems"].(type) {
+			case bool:
+				if !additional {
+					errs = append(errs, &ValidationError{Path: childPointer, Message: "item is not allowed"})
+				}
+			case map[string]interface{}:
+				errs = append(errs, validator.validate(document, additional, item, childPointer)...)
+			}
+		}
+	}
+
+	return errs
+}
+
+// resolve returns the document and the schema referenced by ref.
+func (validator *jsonSchemaValidator) resolve(document interface{}, ref string) (interface{}, interface{}, error) {
+	base, fragment := ref, ""
+	if i := strings.Index(ref, "#"); i != -1 {
+		base, fragment = ref[:i], ref[i+1:]
+	}
+	if base != "" {
+		var ok bool
+		if document, ok = validator.documents[base]; !ok {
+			return nil, nil, fmt.Errorf("unknown schema %s", ref)
+		}
+	}
+	schema, ok := JsonPointerGet(document, fragment)
+	if !ok {
+		return nil, nil, fmt.Errorf("can not resolve schema %s", ref)
+	}
+	return document, schema, nil
+}
+
+func (validator *jsonSchemaValidator) pattern(pattern string) *regexp.Regexp {
+	if re, ok := validator.patterns[pattern]; ok {
+		return re
+	}
+	re, _ := regexp.Compile(pattern)
+	validator.patterns[pattern] = re
+	return re
+}
+
+// JsonPointerGet returns the value of the decoded json document at the JSON Pointer (RFC 6901) pointer.
+func JsonPointerGet(document interface{}, pointer string) (interface{}, bool) {
+	if pointer == "" {
+		return document, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		// Fragments like #/definitions/a%20b are percent-encoded, refer to RFC 6901 section 6
+		if unescaped, err := url.PathUnescape(token); err == nil {
+			token = unescaped
+		}
+		token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+		switch value := document.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if document, ok = value[token]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(value) {
+				return nil, false
+			}
+			document = value[i]
+		default:
+			return nil, false
+		}
+	}
+	return document, true
+}
+
+func escapeJsonPointer(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+func matchesJsonType(types interface{}, instance interface{}) bool {
+	switch types := types.(type) {
+	case string:
+		return matchesJsonTypeName(types, instance)
+	case []interface{}:
+		for _, name := range types {
+			if name, ok := name.(string); ok && matchesJsonTypeName(name, instance) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchesJsonTypeName(name string, instance interface{}) bool {
+	actual := jsonTypeOf(instance)
+	if name == "number" && actual == "integer" {
+		return true
+	}
+	return name == actual
+}
+
+func jsonTypeOf(instance interface{}) string {
+	switch value := instance.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if value == math.Trunc(value) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", instance)
+}
+
+func jsonTypeNames(types interface{}) string {
+	if name, ok := types.(string); ok {
+		return name
+	}
+	return compactJson(types)
+}
+
+func compactJson(v interface{}) string {
+	output, _ := json.Marshal(v)
+	return string(output)
+}
@@ -0,0 +1,123 @@
+package mswagger
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+)
+
+// Types of the swagger 2.0 schemas a swaggertype tag or a @SwaggerType comment can set
+var swaggerTypeNames = map[string]bool{
+	"string":  true,
+	"integer": true,
+	"number":  true,
+	"boolean": true,
+	"object":  true,
+	"file":    true,
+}
+
+// parseSwaggerType parses swagger types like string,date of swaggertype tags and @SwaggerType
+// comments, go types like int64 are mapped by mappings. Empty types are any value.
+func (mappings TypeMappings) parseSwaggerType(text string) (*SwaggerType, error) {
+	parts := strings.Split(strings.Replace(text, " ", "", -1), ",")
+	if parts[0] == "" {
+		return &SwaggerType{}, nil
+	}
+	if swaggerType := mappings[parts[0]]; swaggerType != nil && len(parts) == 1 {
+		mapped := *swaggerType
+		return &mapped, nil
+	}
+	if !swaggerTypeNames[parts[0]] {
+		return nil, fmt.Errorf("Can not parse swagger type %s.", text)
+	}
+	swaggerType := &SwaggerType{Type: parts[0]}
+	if len(parts) > 1 {
+		swaggerType.Format = parts[1]
+	}
+	return swaggerType, nil
+}
+
+// parseSwaggerTypeComment returns the swagger type of the @SwaggerType comment of doc.
+func parseSwaggerTypeComment(doc *ast.CommentGroup) (string, bool) {
+	if doc == nil {
+		return "", false
+	}
+	for _, comment := range doc.List {
+		fields := strings.Fields(strings.TrimSpace(strings.TrimLeft(comment.Text, "/")))
+		if len(fields) > 1 && strings.ToLower(fields[0]) == "@swaggertype" {
+			return strings.Join(fields[1:], ""), true
+		}
+	}
+	return "", false
+}
+
+// marshalerType returns the swagger type of astTypeSpec of packageName if it is annotated with
+// @SwaggerType, listed in TypesImplementingMarshalInterface or has a MarshalJSON or MarshalText
+// method. Types marshalled to text are strings, those marshalled to JSON can be any value.
+func (parser *Parser) marshalerType(astTypeSpec *ast.TypeSpec, packageName string) (string, bool) {
+	if swaggerType, ok := parseSwaggerTypeComment(astTypeSpec.Doc); ok {
+		return swaggerType, true
+	}
+	if swaggerType, ok := parser.TypesImplementingMarshalInterface[astTypeSpec.Name.Name]; ok {
+		return swaggerType, true
+	}
+	methods := parser.receiverMethods(packageName)[astTypeSpec.Name.Name]
+	if methods["MarshalJSON"] {
+		return "", true
+	}
+	if methods["MarshalText"] {
+		return "string", true
+	}
+	return "", false
+}
+
+// setSwaggerType makes the model a type of the swagger type text like string,date, properties
+// of it are inlined unless it is an array like array,number.
+func (m *Model) setSwaggerType(text string, astTypeSpec *ast.TypeSpec, modelPackage string) error {
+	mappings := m.parser.TypeMappings
+	parts := strings.Split(text, ",")
+	if parts[0] == "array" {
+		items, err := mappings.parseSwaggerType(strings.Join(parts[1:], ","))
+		if err != nil {
+			return err
+		}
+		m.schema = &SchemaObject{Type: "array", Items: &SchemaObjectOrArray{Schema: &SchemaObject{Type: items.Type, Format: items.Format, Minimum: items.Minimum}}}
+		return nil
+	}
+
+	swaggerType, err := mappings.parseSwaggerType(text)
+	if err != nil {
+		return err
+	}
+	typeName := modelPackage + "." + astTypeSpec.Name.Name
+	m.parser.swaggerTypes[typeName] = swaggerType
+	m.parser.typeDefTranslations[m.Id] = typeName
+	m.schema = m.parser.swaggerTypes.Schema(typeName)
+	return nil
+}
+
+// setPropertySwaggerType sets the swagger type text of a swaggertype tag or of
+// TypesImplementingMarshalInterface, like string or array,number, to property.
+func (m *Model) setPropertySwaggerType(property *ModelProperty, text string) error {
+	parts := strings.Split(text, ",")
+	isArray := parts[0] == "array"
+	if isArray {
+		text = strings.Join(parts[1:], ",")
+	}
+	swaggerType, err := m.parser.TypeMappings.parseSwaggerType(text)
+	if err != nil {
+		return err
+	}
+
+	property.Ref, property.anonymous, property.Items = "", nil, ModelPropertyItems{}
+	property.Format, property.Minimum = swaggerType.Format, swaggerType.Minimum
+	if isArray {
+		property.Type = "array"
+		property.Items.Type = swaggerType.Type
+	} else {
+		property.Type = swaggerType.Type
+		property.Nullable = property.Nullable || swaggerType.Nullable
+	}
+	property.resolved = true
+	return nil
+}
@@ -0,0 +1,501 @@
+package mswagger
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MergeService is one of the documents combined by MergeSwagger.
+type MergeService struct {
+	// Name is used to namespace colliding definitions and tags.
+	Name string
+	// PathPrefix is prepended to every path of the service.
+	PathPrefix string
+	Swagger    *SwaggerObject
+}
+
+// MergeConflict describes two services which can not be merged without loss.
+type MergeConflict struct {
+	Services [2]string
+	Message  string
+}
+
+func (conflict *MergeConflict) Error() string {
+	return fmt.Sprintf("%s and %s: %s", conflict.Services[0], conflict.Services[1], conflict.Message)
+}
+
+// MergeSwagger combines the documents of several services into one.
+//
+// The paths of every service are prefixed by its PathPrefix and its basePath.
+// Definitions, parameters, responses and tags which are already defined
+// differently by a previous service are renamed to "<Name>.<name>" and the
+// references of the service are updated accordingly. Tags only used by operations
+// are shared. Security definitions and the parameters of paths are united.
+// Schemes, consumes, produces and security are kept at the top level when all
+// services agree on them and are copied into the operations of every service
+// otherwise.
+// Operations using the same path and method, path parameters and security
+// definitions of the same name, which differ between services are reported as
+// conflicts; the first service wins in that case. OperationIds used by several
+// services and differing hosts are reported as conflicts as well.
+//
+// The documents of the services are modified and shared with the result.
+func MergeSwagger(services []*MergeService) (*SwaggerObject, []*MergeConflict) {
+	merged := &SwaggerObject{
+		Swagger: SwaggerVersion,
+		Info:    &InfoObject{},
+		Paths:   map[string]*PathItemObject{},
+	}
+	var conflicts []*MergeConflict
+
+	securityDefinedBy := map[string]string{}
+	operationsDefinedBy := map[string]string{}
+	pathsDefinedBy := map[string]string{}
+	operationIdsDefinedBy := map[string]string{}
+	// Tags declared by a service rather than only used by operations
+	declaredTags := map[string]bool{}
+	hostDefinedBy := ""
+
+	sharedSchemes := sharedByAll(services, func(swagger *SwaggerObject) interface{} { return swagger.Schemes })
+	sharedConsumes := sharedByAll(services, func(swagger *SwaggerObject) interface{} { return swagger.Consumes })
+	sharedProduces := sharedByAll(services, func(swagger *SwaggerObject) interface{} { return swagger.Produces })
+	sharedSecurity := sharedByAll(services, func(swagger *SwaggerObject) interface{} { return swagger.Security })
+
+	for _, service := range services {
+		swagger := service.Swagger
+		if swagger == nil {
+			continue
+		}
+
+		if merged.Info.Title == "" && swagger.Info != nil {
+			info := *swagger.Info
+			merged.Info = &info
+		}
+		if merged.Host == "" {
+			merged.Host = swagger.Host
+			hostDefinedBy = service.Name
+		} else if swagger.Host != "" && swagger.Host != merged.Host {
+			conflicts = append(conflicts, &MergeConflict{
+				Services: [2]string{hostDefinedBy, service.Name},
+				Message:  fmt.Sprintf("host %q differs from %q", merged.Host, swagger.Host),
+			})
+		}
+		if sharedSchemes {
+			merged.Schemes = swagger.Schemes
+		}
+		if sharedConsumes {
+			merged.Consumes = swagger.Consumes
+		}
+		if sharedProduces {
+			merged.Produces = swagger.Produces
+		}
+		if sharedSecurity {
+			merged.Security = swagger.Security
+		}
+		if merged.ExternalDocs == nil {
+			merged.ExternalDocs = swagger.ExternalDocs
+		}
+
+		// Rename colliding components and tags before anything is copied. Components
+		// referring to renamed ones differ as well, so renaming is repeated until the
+		// components of the service which are kept equal those already merged.
+		refs := map[string]string{}
+		for renamed := true; renamed; {
+			renamed = false
+			for name, definition := range swagger.Definitions {
+				ref := "#/definitions/" + name
+				if existing, ok := merged.Definitions[name]; ok && refs[ref] == "" && !renamedEqual(existing, definition, refs) {
+					refs[ref] = "#/definitions/" + namespacedName(service.Name, name, definitionExists(merged))
+					renamed = true
+				}
+			}
+			for name, parameter := range swagger.Parameters {
+				ref := "#/parameters/" + name
+				if existing, ok := merged.Parameters[name]; ok && refs[ref] == "" && !renamedEqual(existing, parameter, refs) {
+					refs[ref] = "#/parameters/" + namespacedName(service.Name, name, parameterExists(merged))
+					renamed = true
+				}
+			}
+			for name, response := range swagger.Responses {
+				ref := "#/responses/" + name
+				if existing, ok := merged.Responses[name]; ok && refs[ref] == "" && !renamedEqual(existing, response, refs) {
+					refs[ref] = "#/responses/" + namespacedName(service.Name, name, responseExists(merged))
+					renamed = true
+				}
+			}
+		}
+		if len(refs) > 0 {
+			RewriteRefs(swagger, func(ref string) string {
+				if newRef, ok := refs[ref]; ok {
+					return newRef
+				}
+				return ref
+			})
+		}
+
+		tags := map[string]string{}
+		for _, tag := range swagger.Tags {
+			existing := findTag(merged.Tags, tag.Name)
+			switch {
+			case existing == nil:
+				merged.Tags = append(merged.Tags, tag)
+			case !declaredTags[tag.Name]:
+				// Tags of operations of previous services get the declaration
+				*existing = *tag
+			case JsonEqual(existing, tag):
+				continue
+			default:
+				newTag := *tag
+				newTag.Name = namespacedName(service.Name, tag.Name, func(name string) bool { return findTag(merged.Tags, name) != nil })
+				tags[tag.Name] = newTag.Name
+				merged.Tags = append(merged.Tags, &newTag)
+				declaredTags[newTag.Name] = true
+				continue
+			}
+			declaredTags[tag.Name] = true
+		}
+		for _, name := range usedTags(swagger) {
+			if findTag(merged.Tags, name) == nil && findTag(swagger.Tags, name) == nil {
+				merged.Tags = append(merged.Tags, &TagObject{Name: name})
+			}
+		}
+
+		for name, definition := range swagger.Definitions {
+			name = strings.TrimPrefix(mapRef(refs, "#/definitions/"+name), "#/definitions/")
+			if _, ok := merged.Definitions[name]; ok {
+				continue
+			}
+			if merged.Definitions == nil {
+				merged.Definitions = map[string]*SchemaObject{}
+			}
+			merged.Definitions[name] = definition
+		}
+		for name, parameter := range swagger.Parameters {
+			name = strings.TrimPrefix(mapRef(refs, "#/parameters/"+name), "#/parameters/")
+			if _, ok := merged.Parameters[name]; ok {
+				continue
+			}
+			if merged.Parameters == nil {
+				merged.Parameters = map[string]*ParameterObject{}
+			}
+			merged.Parameters[name] = parameter
+		}
+		for name, response := range swagger.Responses {
+			name = strings.TrimPrefix(mapRef(refs, "#/responses/"+name), "#/responses/")
+			if _, ok := merged.Responses[name]; ok {
+				continue
+			}
+			if merged.Responses == nil {
+				merged.Responses = map[string]*ResponseObject{}
+			}
+			merged.Responses[name] = response
+		}
+
+		for name, securityDefinition := range swagger.SecurityDefinitions {
+			if existing, ok := merged.SecurityDefinitions[name]; ok {
+				if !JsonEqual(existing, securityDefinition) {
+					conflicts = append(conflicts, &MergeConflict{
+						Services: [2]string{securityDefinedBy[name], service.Name},
+						Message:  fmt.Sprintf("security definition %q differs", name),
+					})
+				}
+				continue
+			}
+			if merged.SecurityDefinitions == nil {
+				merged.SecurityDefinitions = map[string]*SecuritySchemeObject{}
+			}
+			merged.SecurityDefinitions[name] = securityDefinition
+			securityDefinedBy[name] = service.Name
+		}
+
+		for _, itemPath := range sortedPaths(swagger.Paths) {
+			item := swagger.Paths[itemPath]
+			fullPath := path.Join("/", service.PathPrefix, swagger.BasePath, itemPath)
+			if strings.HasSuffix(itemPath, "/") && !strings.HasSuffix(fullPath, "/") {
+				fullPath += "/"
+			}
+
+			mergedItem, ok := merged.Paths[fullPath]
+			if !ok {
+				mergedItem = &PathItemObject{Ref: item.Ref}
+				merged.Paths[fullPath] = mergedItem
+				pathsDefinedBy[fullPath] = service.Name
+			}
+			for _, parameter := range item.Parameters {
+				existing := findParameter(mergedItem.Parameters, parameter)
+				if existing == nil {
+					mergedItem.Parameters = append(mergedItem.Parameters, parameter)
+					continue
+				}
+				if !JsonEqual(existing, parameter) {
+					conflicts = append(conflicts, &MergeConflict{
+						Services: [2]string{pathsDefinedBy[fullPath], service.Name},
+						Message:  fmt.Sprintf("parameter %s of path %s differs", parameterName(parameter), fullPath),
+					})
+				}
+			}
+			for _, method := range Methods {
+				operation, ok := item.Operations()[method]
+				if !ok {
+					continue
+				}
+				for i, tag := range operation.Tags {
+					if newTag, ok := tags[tag]; ok {
+						operation.Tags[i] = newTag
+					}
+				}
+				if !sharedSchemes && operation.Schemes == nil {
+					operation.Schemes = swagger.Schemes
+				}
+				if !sharedConsumes && operation.Consumes == nil {
+					operation.Consumes = swagger.Consumes
+				}
+				if !sharedProduces && operation.Produces == nil {
+					operation.Produces = swagger.Produces
+				}
+				if !sharedSecurity && operation.Security == nil {
+					operation.Security = swagger.Security
+				}
+				if existing, ok := mergedItem.Operations()[method]; ok {
+					if !JsonEqual(existing, operation) {
+						conflicts = append(conflicts, &MergeConflict{
+							Services: [2]string{operationsDefinedBy[method+" "+fullPath], service.Name},
+							Message:  fmt.Sprintf("operation %s %s differs", method, fullPath),
+						})
+					}
+					continue
+				}
+				if operation.OperationId != "" {
+					if definedBy, ok := operationIdsDefinedBy[operation.OperationId]; ok && definedBy != service.Name {
+						conflicts = append(conflicts, &MergeConflict{
+							Services: [2]string{definedBy, service.Name},
+							Message:  fmt.Sprintf("operationId %q is used by both", operation.OperationId),
+						})
+					} else {
+						operationIdsDefinedBy[operation.OperationId] = service.Name
+					}
+				}
+				mergedItem.SetOperation(method, operation)
+				operationsDefinedBy[method+" "+fullPath] = service.Name
+			}
+		}
+	}
+
+	return merged, conflicts
+}
+
+// SetOperation sets the operation of the path item for the upper case http method.
+func (item *PathItemObject) SetOperation(method string, operation *OperationObject) {
+	switch method {
+	case "GET":
+		item.Get = operation
+	case "POST":
+		item.Post = operation
+	case "PUT":
+		item.Put = operation
+	case "PATCH":
+		item.Patch = operation
+	case "DELETE":
+		item.Delete = operation
+	case "OPTIONS":
+		item.Options = operation
+	case "HEAD":
+		item.Head = operation
+	}
+}
+
+// RewriteRefs replaces every "$ref" found in v by the result of rewrite.
+// v must be a pointer, e.g. a *SwaggerObject.
+func RewriteRefs(v interface{}, rewrite func(string) string) {
+	rewriteRefs(reflect.ValueOf(v), rewrite)
+}
+
+func rewriteRefs(v reflect.Value, rewrite func(string) string) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			rewriteRefs(v.Elem(), rewrite)
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		elem := v.Elem()
+		if elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Map {
+			rewriteRefs(elem, rewrite)
+			return
+		}
+		// Values stored in interfaces are not addressable, work on a copy.
+		elemCopy := reflect.New(elem.Type()).Elem()
+		elemCopy.Set(elem)
+		rewriteRefs(elemCopy, rewrite)
+		if v.CanSet() {
+			v.Set(elemCopy)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			if strings.Split(field.Tag.Get("json"), ",")[0] == "$ref" && field.Type.Kind() == reflect.String {
+				if ref := v.Field(i).String(); ref != "" && v.Field(i).CanSet() {
+					v.Field(i).SetString(rewrite(ref))
+				}
+				continue
+			}
+			rewriteRefs(v.Field(i), rewrite)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			rewriteRefs(v.Index(i), rewrite)
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			value := v.MapIndex(key)
+			if key.Kind() == reflect.String && key.String() == "$ref" {
+				if ref, ok := value.Interface().(string); ok {
+					v.SetMapIndex(key, reflect.ValueOf(rewrite(ref)).Convert(value.Type()))
+				}
+				continue
+			}
+			valueCopy := reflect.New(value.Type()).Elem()
+			valueCopy.Set(value)
+			rewriteRefs(valueCopy, rewrite)
+			v.SetMapIndex(key, valueCopy)
+		}
+	}
+}
+
+// sharedByAll reports whether value is the same for the documents of all services.
+// Empty lists equal missing ones.
+func sharedByAll(services []*MergeService, value func(*SwaggerObject) interface{}) bool {
+	var first interface{}
+	found := false
+	for _, service := range services {
+		if service.Swagger == nil {
+			continue
+		}
+		v := value(service.Swagger)
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice && rv.Len() == 0 {
+			v = nil
+		}
+		if !found {
+			first, found = v, true
+		} else if !JsonEqual(first, v) {
+			return false
+		}
+	}
+	return true
+}
+
+func namespacedName(serviceName, name string, exists func(string) bool) string {
+	newName := serviceName + "." + name
+	for i := 2; exists(newName); i++ {
+		newName = fmt.Sprintf("%s%d.%s", serviceName, i, name)
+	}
+	return newName
+}
+
+func definitionExists(swagger *SwaggerObject) func(string) bool {
+	return func(name string) bool {
+		_, ok := swagger.Definitions[name]
+		return ok
+	}
+}
+
+func parameterExists(swagger *SwaggerObject) func(string) bool {
+	return func(name string) bool {
+		_, ok := swagger.Parameters[name]
+		return ok
+	}
+}
+
+func responseExists(swagger *SwaggerObject) func(string) bool {
+	return func(name string) bool {
+		_, ok := swagger.Responses[name]
+		return ok
+	}
+}
+
+// renamedEqual reports whether the component value of a service equals existing
+// once the references of value are renamed by refs.
+func renamedEqual(existing, value interface{}, refs map[string]string) bool {
+	var renamed interface{}
+	if data, err := json.Marshal(value); err != nil || json.Unmarshal(data, &renamed) != nil {
+		return false
+	}
+	RewriteRefs(&renamed, func(ref string) string {
+		return mapRef(refs, ref)
+	})
+	return JsonEqual(existing, renamed)
+}
+
+func mapRef(refs map[string]string, ref string) string {
+	if newRef, ok := refs[ref]; ok {
+		return newRef
+	}
+	return ref
+}
+
+// usedTags returns the names of the tags used by the operations of swagger.
+func usedTags(swagger *SwaggerObject) []string {
+	var names []string
+	for _, itemPath := range sortedPaths(swagger.Paths) {
+		for _, method := range Methods {
+			if operation, ok := swagger.Paths[itemPath].Operations()[method]; ok {
+				for _, name := range operation.Tags {
+					if !IsInStringList(names, name) {
+						names = append(names, name)
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+func findTag(tags []*TagObject, name string) *TagObject {
+	for _, tag := range tags {
+		if tag.Name == name {
+			return tag
+		}
+	}
+	return nil
+}
+
+// findParameter returns the parameter of parameters with the same reference, or name and location, as parameter.
+func findParameter(parameters []*ParameterObject, parameter *ParameterObject) *ParameterObject {
+	for _, existing := range parameters {
+		if existing.Ref != "" || parameter.Ref != "" {
+			if existing.Ref == parameter.Ref {
+				return existing
+			}
+			continue
+		}
+		if existing.Name == parameter.Name && existing.In == parameter.In {
+			return existing
+		}
+	}
+	return nil
+}
+
+func parameterName(parameter *ParameterObject) string {
+	if parameter.Ref != "" {
+		return parameter.Ref
+	}
+	return parameter.In + " " + parameter.Name
+}
+
+func sortedPaths(paths map[string]*PathItemObject) []string {
+	keys := make([]string, 0, len(paths))
+	for key, _ := range paths {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
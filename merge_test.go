@@ -0,0 +1,146 @@
+package mswagger
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMergeSwagger(t *testing.T) {
+	tests := []struct {
+		name      string
+		services  []string
+		expected  string
+		conflicts []string
+	}{
+		{
+			name: "definitions referring to renamed definitions are renamed",
+			services: []string{
+				`{"paths": {"/a": {"get": {"responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/User"}}}}}},
+				  "definitions": {
+				    "User": {"type": "object", "properties": {"address": {"$ref": "#/definitions/Address"}}},
+				    "Address": {"type": "object", "properties": {"street": {"type": "string"}}}}}`,
+				`{"paths": {"/b": {"get": {"responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/User"}}}}}},
+				  "definitions": {
+				    "User": {"type": "object", "properties": {"address": {"$ref": "#/definitions/Address"}}},
+				    "Address": {"type": "object", "properties": {"city": {"type": "string"}}}}}`,
+			},
+			expected: `{"swagger": "2.0", "info": {"title": ""},
+			  "paths": {
+			    "/a": {"get": {"responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/User"}}}}},
+			    "/b": {"get": {"responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/s1.User"}}}}}},
+			  "definitions": {
+			    "User": {"type": "object", "properties": {"address": {"$ref": "#/definitions/Address"}}},
+			    "Address": {"type": "object", "properties": {"street": {"type": "string"}}},
+			    "s1.User": {"type": "object", "properties": {"address": {"$ref": "#/definitions/s1.Address"}}},
+			    "s1.Address": {"type": "object", "properties": {"city": {"type": "string"}}}}}`,
+		},
+		{
+			name: "equal definitions are shared",
+			services: []string{
+				`{"paths": {}, "definitions": {"User": {"type": "object", "properties": {"address": {"$ref": "#/definitions/Address"}}},
+				  "Address": {"type": "string"}}}`,
+				`{"paths": {}, "definitions": {"User": {"type": "object", "properties": {"address": {"$ref": "#/definitions/Address"}}},
+				  "Address": {"type": "string"}}}`,
+			},
+			expected: `{"swagger": "2.0", "info": {"title": ""}, "paths": {},
+			  "definitions": {"User": {"type": "object", "properties": {"address": {"$ref": "#/definitions/Address"}}},
+			    "Address": {"type": "string"}}}`,
+		},
+		{
+			name: "parameters of paths are united",
+			services: []string{
+				`{"paths": {"/items/{id}": {"parameters": [{"name": "id", "in": "path", "required": true, "type": "string"}],
+				  "get": {"operationId": "getItem", "responses": {"200": {"description": "ok"}}}}}}`,
+				`{"paths": {"/items/{id}": {"parameters": [{"name": "id", "in": "path", "required": true, "type": "integer"},
+				  {"name": "X-Tenant", "in": "header", "type": "string"}],
+				  "put": {"operationId": "getItem", "responses": {"200": {"description": "ok"}}}}}}`,
+			},
+			expected: `{"swagger": "2.0", "info": {"title": ""},
+			  "paths": {"/items/{id}": {
+			    "parameters": [{"name": "id", "in": "path", "required": true, "type": "string"}, {"name": "X-Tenant", "in": "header", "type": "string"}],
+			    "get": {"operationId": "getItem", "responses": {"200": {"description": "ok"}}},
+			    "put": {"operationId": "getItem", "responses": {"200": {"description": "ok"}}}}}}`,
+			conflicts: []string{
+				"s0 and s1: parameter path id of path /items/{id} differs",
+				`s0 and s1: operationId "getItem" is used by both`,
+			},
+		},
+		{
+			name: "tags only used by operations are shared",
+			services: []string{
+				`{"tags": [{"name": "users", "description": "Users"}],
+				  "paths": {"/a": {"get": {"tags": ["users", "shared"], "responses": {"200": {"description": "ok"}}}}}}`,
+				`{"tags": [{"name": "shared", "description": "Shared"}],
+				  "paths": {"/b": {"get": {"tags": ["users"], "responses": {"200": {"description": "ok"}}}}}}`,
+			},
+			expected: `{"swagger": "2.0", "info": {"title": ""},
+			  "tags": [{"name": "users", "description": "Users"}, {"name": "shared", "description": "Shared"}],
+			  "paths": {
+			    "/a": {"get": {"tags": ["users", "shared"], "responses": {"200": {"description": "ok"}}}},
+			    "/b": {"get": {"tags": ["users"], "responses": {"200": {"description": "ok"}}}}}}`,
+		},
+		{
+			name: "top level values shared by all services are kept",
+			services: []string{
+				`{"host": "api.example.com", "schemes": ["https"], "produces": ["application/json"], "security": [{"key": []}],
+				  "paths": {"/a": {"get": {"responses": {"200": {"description": "ok"}}}}}}`,
+				`{"host": "api.example.com", "schemes": ["https"], "produces": ["application/json"], "security": [{"key": []}],
+				  "paths": {"/b": {"get": {"responses": {"200": {"description": "ok"}}}}}}`,
+			},
+			expected: `{"swagger": "2.0", "info": {"title": ""}, "host": "api.example.com",
+			  "schemes": ["https"], "produces": ["application/json"], "security": [{"key": []}],
+			  "paths": {
+			    "/a": {"get": {"responses": {"200": {"description": "ok"}}}},
+			    "/b": {"get": {"responses": {"200": {"description": "ok"}}}}}}`,
+		},
+		{
+			name: "differing top level values are copied into the operations",
+			services: []string{
+				`{"host": "a.example.com", "schemes": ["https"], "consumes": ["application/json"], "security": [{"key": []}],
+				  "paths": {"/a": {"get": {"responses": {"200": {"description": "ok"}}},
+				    "post": {"consumes": ["text/plain"], "security": [], "responses": {"200": {"description": "ok"}}}}}}`,
+				`{"host": "b.example.com", "schemes": ["http", "https"], "consumes": ["application/json"],
+				  "paths": {"/b": {"get": {"responses": {"200": {"description": "ok"}}}}}}`,
+			},
+			expected: `{"swagger": "2.0", "info": {"title": ""}, "host": "a.example.com", "consumes": ["application/json"],
+			  "paths": {
+			    "/a": {
+			      "get": {"schemes": ["https"], "security": [{"key": []}], "responses": {"200": {"description": "ok"}}},
+			      "post": {"schemes": ["https"], "consumes": ["text/plain"], "security": [], "responses": {"200": {"description": "ok"}}}},
+			    "/b": {"get": {"schemes": ["http", "https"], "responses": {"200": {"description": "ok"}}}}}}`,
+			conflicts: []string{
+				`s0 and s1: host "a.example.com" differs from "b.example.com"`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		var services []*MergeService
+		for i, document := range test.services {
+			swagger := &SwaggerObject{}
+			if err := json.Unmarshal([]byte(document), swagger); err != nil {
+				t.Fatalf("%s: %v", test.name, err)
+			}
+			services = append(services, &MergeService{Name: "s" + string('0'+rune(i)), Swagger: swagger})
+		}
+
+		merged, conflicts := MergeSwagger(services)
+		var expected interface{}
+		if err := json.Unmarshal([]byte(test.expected), &expected); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !JsonEqual(merged, expected) {
+			actual, _ := json.MarshalIndent(merged, "", "  ")
+			t.Errorf("%s: unexpected merged document\n%s", test.name, actual)
+		}
+		if len(conflicts) != len(test.conflicts) {
+			t.Errorf("%s: expected conflicts %v, got %v", test.name, test.conflicts, conflicts)
+			continue
+		}
+		for i, conflict := range conflicts {
+			if conflict.Error() != test.conflicts[i] {
+				t.Errorf("%s: expected conflict %q, got %q", test.name, test.conflicts[i], conflict.Error())
+			}
+		}
+	}
+}
@@ -0,0 +1,610 @@
+package mswagger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// OpenAPI3Version is the version of the documents returned by ConvertToOpenAPI3.
+const OpenAPI3Version = "3.0.3"
+
+// openAPI3Converter converts the JSON form of a swagger 2.0 document.
+type openAPI3Converter struct {
+	swagger map[string]interface{}
+	// discriminator objects of polymorphic definitions by their references
+	discriminators map[string]map[string]interface{}
+}
+
+// Keys of parameters, items and headers which are part of their schema in OpenAPI 3
+var parameterSchemaKeys = []string{
+	"type", "format", "items", "default", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+	"maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "enum", "multipleOf",
+}
+
+// ConvertToOpenAPI3 converts swagger into an OpenAPI 3.0 document.
+//
+// Definitions, parameters, responses and security definitions become components
+// and the references to them are updated. Body and formData parameters become
+// the requestBody of their operations, with the media types of consumes, files
+// binary strings. Host, basePath and schemes become servers. References to
+// definitions with a discriminator, other than those of the allOf of their
+// subtypes, become a oneOf of the subtypes with the discriminator mapping.
+// The x-nullable and x-writeOnly extensions of schemas become nullable and writeOnly.
+func ConvertToOpenAPI3(swagger *SwaggerObject) (map[string]interface{}, error) {
+	document, err := swaggerDocument(swagger)
+	if err != nil {
+		return nil, err
+	}
+	source, ok := document.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Can not convert %T to OpenAPI 3.", document)
+	}
+	converter := &openAPI3Converter{swagger: source, discriminators: map[string]map[string]interface{}{}}
+	return converter.convert(), nil
+}
+
+// WriteOpenAPI3 converts swagger into an OpenAPI 3.0 document and writes it as indented json to OutputPath.
+func WriteOpenAPI3(swagger *SwaggerObject, OutputPath string) error {
+	openapi, err := ConvertToOpenAPI3(swagger)
+	if err != nil {
+		return err
+	}
+	output, err := json.MarshalIndent(openapi, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(OutputPath, output, 0644)
+}
+
+func (converter *openAPI3Converter) convert() map[string]interface{} {
+	swagger := converter.swagger
+	openapi := map[string]interface{}{
+		"openapi": OpenAPI3Version,
+		"info":    swagger["info"],
+	}
+	copyExtensions(openapi, swagger)
+	for _, key := range []string{"tags", "externalDocs", "security"} {
+		if value, ok := swagger[key]; ok {
+			openapi[key] = value
+		}
+	}
+	if servers := converter.servers(stringList(swagger["schemes"])); servers != nil {
+		openapi["servers"] = servers
+	}
+
+	components := map[string]interface{}{}
+	if definitions := jsonObject(swagger["definitions"]); len(definitions) > 0 {
+		converter.findDiscriminators(definitions)
+		schemas := map[string]interface{}{}
+		for name, schema := range definitions {
+			schemas[name] = converter.schema(schema)
+			if discriminator, ok := converter.discriminators["#/definitions/"+name]; ok {
+				schemas[name].(map[string]interface{})["discriminator"] = discriminator
+			}
+		}
+		components["schemas"] = schemas
+	}
+	parameters := map[string]interface{}{}
+	requestBodies := map[string]interface{}{}
+	for name, parameter := range jsonObject(swagger["parameters"]) {
+		parameter := jsonObject(parameter)
+		switch parameter["in"] {
+		case "body":
+			requestBodies[name] = converter.requestBody(parameter, converter.mediaTypes(nil, "consumes"))
+		case "formData":
+			// Form fields are properties of the request bodies of the operations using them
+		default:
+			parameters[name] = converter.parameter(parameter)
+		}
+	}
+	if len(parameters) > 0 {
+		components["parameters"] = parameters
+	}
+	if len(requestBodies) > 0 {
+		components["requestBodies"] = requestBodies
+	}
+	if responses := jsonObject(swagger["responses"]); len(responses) > 0 {
+		converted := map[string]interface{}{}
+		for name, response := range responses {
+			converted[name] = converter.response(jsonObject(response), converter.mediaTypes(nil, "produces"))
+		}
+		components["responses"] = converted
+	}
+	if securityDefinitions := jsonObject(swagger["securityDefinitions"]); len(securityDefinitions) > 0 {
+		securitySchemes := map[string]interface{}{}
+		for name, securityDefinition := range securityDefinitions {
+			securitySchemes[name] = securityScheme(jsonObject(securityDefinition))
+		}
+		components["securitySchemes"] = securitySchemes
+	}
+	if len(components) > 0 {
+		openapi["components"] = components
+	}
+
+	paths := map[string]interface{}{}
+	for itemPath, item := range jsonObject(swagger["paths"]) {
+		paths[itemPath] = converter.pathItem(jsonObject(item))
+	}
+	openapi["paths"] = paths
+
+	var document interface{} = openapi
+	RewriteRefs(&document, func(ref string) string {
+		for _, component := range [][2]string{{"definitions", "schemas"}, {"parameters", "parameters"}, {"responses", "responses"}} {
+			if strings.HasPrefix(ref, "#/"+component[0]+"/") {
+				return "#/components/" + component[1] + "/" + strings.TrimPrefix(ref, "#/"+component[0]+"/")
+			}
+		}
+		return ref
+	})
+	return openapi
+}
+
+// servers returns the servers of the host and basePath of the document with schemes.
+func (converter *openAPI3Converter) servers(schemes []string) []interface{} {
+	host, _ := converter.swagger["host"].(string)
+	basePath, _ := converter.swagger["basePath"].(string)
+	if host == "" {
+		if basePath == "" {
+			return nil
+		}
+		return []interface{}{map[string]interface{}{"url": basePath}}
+	}
+	if len(schemes) == 0 {
+		return []interface{}{map[string]interface{}{"url": "//" + host + basePath}}
+	}
+	var servers []interface{}
+	for _, scheme := range schemes {
+		servers = append(servers, map[string]interface{}{"url": scheme + "://" + host + basePath})
+	}
+	return servers
+}
+
+// mediaTypes returns the consumes or produces of operation, those of the document
+// if it has none.
+func (converter *openAPI3Converter) mediaTypes(operation map[string]interface{}, key string) []string {
+	if mediaTypes := stringList(operation[key]); len(mediaTypes) > 0 {
+		return mediaTypes
+	}
+	if mediaTypes := stringList(converter.swagger[key]); len(mediaTypes) > 0 {
+		return mediaTypes
+	}
+	return []string{ContentTypeJson}
+}
+
+func (converter *openAPI3Converter) pathItem(item map[string]interface{}) map[string]interface{} {
+	converted := map[string]interface{}{}
+	copyExtensions(converted, item)
+	if ref, ok := item["$ref"]; ok {
+		converted["$ref"] = ref
+	}
+
+	// Body and formData parameters of the path are moved into the request bodies of its operations
+	var shared, parameters []interface{}
+	for _, parameter := range jsonList(item["parameters"]) {
+		if in := converter.resolveParameter(parameter)["in"]; in == "body" || in == "formData" {
+			shared = append(shared, parameter)
+		} else {
+			parameters = append(parameters, converter.parameter(jsonObject(parameter)))
+		}
+	}
+	if len(parameters) > 0 {
+		converted["parameters"] = parameters
+	}
+
+	for _, method := range Methods {
+		key := strings.ToLower(method)
+		if operation := jsonObject(item[key]); operation != nil {
+			converted[key] = converter.operation(operation, shared)
+		}
+	}
+	return converted
+}
+
+func (converter *openAPI3Converter) operation(operation map[string]interface{}, shared []interface{}) map[string]interface{} {
+	converted := map[string]interface{}{}
+	copyExtensions(converted, operation)
+	for _, key := range []string{"tags", "summary", "description", "externalDocs", "operationId", "deprecated", "security"} {
+		if value, ok := operation[key]; ok {
+			converted[key] = value
+		}
+	}
+	if schemes := stringList(operation["schemes"]); len(schemes) > 0 {
+		converted["servers"] = converter.servers(schemes)
+	}
+
+	operationParameters := jsonList(operation["parameters"])
+	for _, parameter := range shared {
+		resolved := converter.resolveParameter(parameter)
+		overridden := false
+		for _, operationParameter := range operationParameters {
+			operationResolved := converter.resolveParameter(operationParameter)
+			if operationResolved["name"] == resolved["name"] && operationResolved["in"] == resolved["in"] {
+				overridden = true
+			}
+		}
+		if !overridden {
+			operationParameters = append(operationParameters, parameter)
+		}
+	}
+
+	consumes := converter.mediaTypes(operation, "consumes")
+	var parameters []interface{}
+	var formData []map[string]interface{}
+	for _, parameter := range operationParameters {
+		resolved := converter.resolveParameter(parameter)
+		switch resolved["in"] {
+		case "body":
+			if ref, ok := jsonObject(parameter)["$ref"].(string); ok {
+				converted["requestBody"] = map[string]interface{}{
+					"$ref": "#/components/requestBodies/" + strings.TrimPrefix(ref, "#/parameters/"),
+				}
+			} else {
+				converted["requestBody"] = converter.requestBody(resolved, consumes)
+			}
+		case "formData":
+			formData = append(formData, resolved)
+		default:
+			parameters = append(parameters, converter.parameter(jsonObject(parameter)))
+		}
+	}
+	if len(parameters) > 0 {
+		converted["parameters"] = parameters
+	}
+	if len(formData) > 0 {
+		converted["requestBody"] = formRequestBody(formData, consumes)
+	}
+
+	produces := converter.mediaTypes(operation, "produces")
+	responses := map[string]interface{}{}
+	for code, response := range jsonObject(operation["responses"]) {
+		if strings.HasPrefix(code, "x-") {
+			responses[code] = response
+			continue
+		}
+		responses[code] = converter.response(jsonObject(response), produces)
+	}
+	converted["responses"] = responses
+	return converted
+}
+
+// resolveParameter returns the parameter of the components parameter refers to.
+func (converter *openAPI3Converter) resolveParameter(parameter interface{}) map[string]interface{} {
+	object := jsonObject(parameter)
+	if ref, ok := object["$ref"].(string); ok {
+		return jsonObject(jsonObject(converter.swagger["parameters"])[strings.TrimPrefix(ref, "#/parameters/")])
+	}
+	return object
+}
+
+func (converter *openAPI3Converter) parameter(parameter map[string]interface{}) map[string]interface{} {
+	if ref, ok := parameter["$ref"]; ok {
+		return map[string]interface{}{"$ref": ref}
+	}
+	converted := map[string]interface{}{}
+	copyExtensions(converted, parameter)
+	for _, key := range []string{"name", "in", "description", "required", "allowEmptyValue"} {
+		if value, ok := parameter[key]; ok {
+			converted[key] = value
+		}
+	}
+	if example, ok := converted["x-example"]; ok {
+		delete(converted, "x-example")
+		converted["example"] = example
+	}
+	converted["schema"] = parameterSchema(parameter)
+
+	if parameter["type"] == "array" {
+		switch parameter["collectionFormat"] {
+		case "multi":
+			converted["style"], converted["explode"] = "form", true
+		case "ssv":
+			converted["style"], converted["explode"] = "spaceDelimited", false
+		case "pipes":
+			converted["style"], converted["explode"] = "pipeDelimited", false
+		case "csv", nil:
+			// Comma separated values are the default of path and header parameters
+			if parameter["in"] == "query" {
+				converted["style"], converted["explode"] = "form", false
+			}
+		}
+	}
+	return converted
+}
+
+// parameterSchema returns the schema of a parameter, items or header.
+func parameterSchema(parameter map[string]interface{}) map[string]interface{} {
+	schema := map[string]interface{}{}
+	for _, key := range parameterSchemaKeys {
+		if value, ok := parameter[key]; ok {
+			schema[key] = value
+		}
+	}
+	if items := jsonObject(schema["items"]); items != nil {
+		schema["items"] = parameterSchema(items)
+	}
+	if schema["type"] == "file" {
+		schema["type"], schema["format"] = "string", "binary"
+	}
+	return schema
+}
+
+func (converter *openAPI3Converter) requestBody(parameter map[string]interface{}, consumes []string) map[string]interface{} {
+	content := map[string]interface{}{}
+	for _, mediaType := range consumes {
+		content[mediaType] = map[string]interface{}{"schema": converter.schema(parameter["schema"])}
+	}
+	requestBody := map[string]interface{}{"content": content}
+	copyExtensions(requestBody, parameter)
+	if description, ok := parameter["description"]; ok {
+		requestBody["description"] = description
+	}
+	if parameter["required"] == true {
+		requestBody["required"] = true
+	}
+	return requestBody
+}
+
+// formRequestBody returns the request body with the formData parameters as properties.
+// Without form media types in consumes, it is multipart/form-data if it has files
+// and application/x-www-form-urlencoded otherwise.
+func formRequestBody(parameters []map[string]interface{}, consumes []string) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []interface{}
+	hasFiles := false
+	for _, parameter := range parameters {
+		name, _ := parameter["name"].(string)
+		property := parameterSchema(parameter)
+		if description, ok := parameter["description"]; ok {
+			property["description"] = description
+		}
+		if example, ok := parameter["x-example"]; ok {
+			property["example"] = example
+		}
+		if property["format"] == "binary" || jsonObject(property["items"])["format"] == "binary" {
+			hasFiles = true
+		}
+		properties[name] = property
+		if parameter["required"] == true {
+			required = append(required, name)
+		}
+	}
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	var mediaTypes []string
+	for _, mediaType := range consumes {
+		if mediaType == ContentTypeMultiPartFormData || mediaType == ContentTypeFormUrlEncoded {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+	}
+	if len(mediaTypes) == 0 {
+		if hasFiles {
+			mediaTypes = []string{ContentTypeMultiPartFormData}
+		} else {
+			mediaTypes = []string{ContentTypeFormUrlEncoded}
+		}
+	}
+	content := map[string]interface{}{}
+	for _, mediaType := range mediaTypes {
+		content[mediaType] = map[string]interface{}{"schema": schema}
+	}
+	requestBody := map[string]interface{}{"content": content}
+	if len(required) > 0 {
+		requestBody["required"] = true
+	}
+	return requestBody
+}
+
+func (converter *openAPI3Converter) response(response map[string]interface{}, produces []string) map[string]interface{} {
+	if ref, ok := response["$ref"]; ok {
+		return map[string]interface{}{"$ref": ref}
+	}
+	converted := map[string]interface{}{"description": response["description"]}
+	copyExtensions(converted, response)
+	if headers := jsonObject(response["headers"]); len(headers) > 0 {
+		convertedHeaders := map[string]interface{}{}
+		for name, header := range headers {
+			header := jsonObject(header)
+			convertedHeader := map[string]interface{}{"schema": parameterSchema(header)}
+			copyExtensions(convertedHeader, header)
+			if description, ok := header["description"]; ok {
+				convertedHeader["description"] = description
+			}
+			convertedHeaders[name] = convertedHeader
+		}
+		converted["headers"] = convertedHeaders
+	}
+
+	content := map[string]interface{}{}
+	if schema, ok := response["schema"]; ok {
+		for _, mediaType := range produces {
+			content[mediaType] = map[string]interface{}{"schema": converter.schema(schema)}
+		}
+	}
+	for mediaType, example := range jsonObject(response["examples"]) {
+		mediaTypeObject := jsonObject(content[mediaType])
+		if mediaTypeObject == nil {
+			mediaTypeObject = map[string]interface{}{}
+			content[mediaType] = mediaTypeObject
+		}
+		mediaTypeObject["example"] = example
+	}
+	if len(content) > 0 {
+		converted["content"] = content
+	}
+	return converted
+}
+
+// Flows of OpenAPI 3 by the flow of swagger 2.0
+var oauth2Flows = map[string]string{
+	"implicit":    "implicit",
+	"password":    "password",
+	"application": "clientCredentials",
+	"accessCode":  "authorizationCode",
+}
+
+func securityScheme(scheme map[string]interface{}) map[string]interface{} {
+	converted := map[string]interface{}{}
+	copyExtensions(converted, scheme)
+	if description, ok := scheme["description"]; ok {
+		converted["description"] = description
+	}
+	switch scheme["type"] {
+	case "basic":
+		converted["type"], converted["scheme"] = "http", "basic"
+	case "apiKey":
+		converted["type"], converted["name"], converted["in"] = "apiKey", scheme["name"], scheme["in"]
+	case "oauth2":
+		flow := map[string]interface{}{"scopes": map[string]interface{}{}}
+		if scopes, ok := scheme["scopes"]; ok {
+			flow["scopes"] = scopes
+		}
+		for _, key := range []string{"authorizationUrl", "tokenUrl"} {
+			if value, ok := scheme[key]; ok {
+				flow[key] = value
+			}
+		}
+		flowName, _ := scheme["flow"].(string)
+		converted["type"] = "oauth2"
+		converted["flows"] = map[string]interface{}{oauth2Flows[flowName]: flow}
+	}
+	return converted
+}
+
+// findDiscriminators collects the discriminators of definitions, mapping the
+// x-discriminator-value or name of the subtypes to their references.
+func (converter *openAPI3Converter) findDiscriminators(definitions map[string]interface{}) {
+	for name, definition := range definitions {
+		propertyName, ok := jsonObject(definition)["discriminator"].(string)
+		if !ok {
+			continue
+		}
+		mapping := map[string]interface{}{}
+		for subtypeName, subtype := range definitions {
+			for _, schema := range jsonList(jsonObject(subtype)["allOf"]) {
+				if jsonObject(schema)["$ref"] != "#/definitions/"+name {
+					continue
+				}
+				value, ok := jsonObject(subtype)["x-discriminator-value"].(string)
+				if !ok {
+					value = subtypeName
+				}
+				mapping[value] = "#/components/schemas/" + subtypeName
+			}
+		}
+		discriminator := map[string]interface{}{"propertyName": propertyName}
+		if len(mapping) > 0 {
+			discriminator["mapping"] = mapping
+		}
+		converter.discriminators["#/definitions/"+name] = discriminator
+	}
+}
+
+// schema converts a swagger 2.0 schema into an OpenAPI 3.0 one.
+func (converter *openAPI3Converter) schema(schema interface{}) interface{} {
+	object, ok := schema.(map[string]interface{})
+	if !ok {
+		return schema
+	}
+	if ref, ok := object["$ref"].(string); ok {
+		if discriminator, ok := converter.discriminators[ref]; ok && len(object) == 1 {
+			mapping := jsonObject(discriminator["mapping"])
+			values := make([]string, 0, len(mapping))
+			for value, _ := range mapping {
+				values = append(values, value)
+			}
+			sort.Strings(values)
+			var subtypes []interface{}
+			for _, value := range values {
+				subtypes = append(subtypes, map[string]interface{}{"$ref": mapping[value]})
+			}
+			if len(subtypes) > 0 {
+				return map[string]interface{}{"oneOf": subtypes, "discriminator": discriminator}
+			}
+		}
+	}
+	converted := map[string]interface{}{}
+	for key, value := range object {
+		switch key {
+		case "properties":
+			properties := map[string]interface{}{}
+			for name, property := range jsonObject(value) {
+				properties[name] = converter.schema(property)
+			}
+			converted[key] = properties
+		case "items":
+			if tuple, ok := value.([]interface{}); ok {
+				// OpenAPI 3.0 has no tuples, the items are any of their schemas
+				var schemas []interface{}
+				for _, item := range tuple {
+					schemas = append(schemas, converter.schema(item))
+				}
+				converted[key] = map[string]interface{}{"anyOf": schemas}
+			} else {
+				converted[key] = converter.schema(value)
+			}
+		case "allOf":
+			// Subtypes and wrapped references keep referring to polymorphic definitions
+			var schemas []interface{}
+			for _, item := range jsonList(value) {
+				if _, ok := jsonObject(item)["$ref"]; ok {
+					schemas = append(schemas, item)
+				} else {
+					schemas = append(schemas, converter.schema(item))
+				}
+			}
+			converted[key] = schemas
+		case "anyOf", "oneOf":
+			var schemas []interface{}
+			for _, item := range jsonList(value) {
+				schemas = append(schemas, converter.schema(item))
+			}
+			converted[key] = schemas
+		case "additionalProperties", "not":
+			converted[key] = converter.schema(value)
+		case "x-nullable":
+			converted["nullable"] = value
+		case "x-writeOnly":
+			converted["writeOnly"] = value
+		default:
+			converted[key] = value
+		}
+	}
+	if converted["type"] == "file" {
+		converted["type"], converted["format"] = "string", "binary"
+	}
+	return converted
+}
+
+func copyExtensions(dst, src map[string]interface{}) {
+	for key, value := range src {
+		if strings.HasPrefix(key, "x-") {
+			dst[key] = value
+		}
+	}
+}
+
+func jsonObject(value interface{}) map[string]interface{} {
+	object, _ := value.(map[string]interface{})
+	return object
+}
+
+func jsonList(value interface{}) []interface{} {
+	list, _ := value.([]interface{})
+	return list
+}
+
+func stringList(value interface{}) []string {
+	var list []string
+	for _, item := range jsonList(value) {
+		if s, ok := item.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
@@ -0,0 +1,162 @@
+package mswagger
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestConvertToOpenAPI3(t *testing.T) {
+	tests := []struct {
+		name     string
+		swagger  string
+		expected string
+	}{
+		{
+			name:    "servers",
+			swagger: `{"swagger": "2.0", "info": {"title": "Users", "version": "1.0"}, "host": "api.example.com", "basePath": "/v1", "schemes": ["https"], "paths": {}}`,
+			expected: `{"openapi": "3.0.3", "info": {"title": "Users", "version": "1.0"},
+			  "servers": [{"url": "https://api.example.com/v1"}], "paths": {}}`,
+		},
+		{
+			name: "body parameter and responses",
+			swagger: `{"swagger": "2.0", "info": {"title": ""}, "consumes": ["application/json"], "produces": ["application/json"],
+			  "paths": {"/users": {"post": {
+			    "parameters": [{"name": "user", "in": "body", "description": "New user.", "required": true, "schema": {"$ref": "#/definitions/User"}}],
+			    "responses": {"201": {"description": "Created", "schema": {"$ref": "#/definitions/User"},
+			      "headers": {"Location": {"type": "string", "description": "Url of the user."}}},
+			      "404": {"$ref": "#/responses/NotFound"}}}}},
+			  "definitions": {"User": {"type": "object", "properties": {"name": {"type": "string"}}}},
+			  "responses": {"NotFound": {"description": "Not found"}}}`,
+			expected: `{"openapi": "3.0.3", "info": {"title": ""},
+			  "paths": {"/users": {"post": {
+			    "requestBody": {"description": "New user.", "required": true,
+			      "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}},
+			    "responses": {"201": {"description": "Created",
+			      "headers": {"Location": {"description": "Url of the user.", "schema": {"type": "string"}}},
+			      "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}},
+			      "404": {"$ref": "#/components/responses/NotFound"}}}}},
+			  "components": {
+			    "schemas": {"User": {"type": "object", "properties": {"name": {"type": "string"}}}},
+			    "responses": {"NotFound": {"description": "Not found"}}}}`,
+		},
+		{
+			name: "files and form fields",
+			swagger: `{"swagger": "2.0", "info": {"title": ""},
+			  "paths": {"/users/{id}/photos": {"post": {"consumes": ["multipart/form-data"],
+			    "parameters": [
+			      {"name": "id", "in": "path", "required": true, "type": "integer", "format": "int64"},
+			      {"name": "avatar", "in": "formData", "required": true, "type": "file"},
+			      {"name": "photos", "in": "formData", "type": "array", "items": {"type": "string", "format": "binary"}},
+			      {"name": "caption", "in": "formData", "description": "Caption of the photos.", "type": "string"}],
+			    "responses": {"204": {"description": "No Content"}}}}}}`,
+			expected: `{"openapi": "3.0.3", "info": {"title": ""},
+			  "paths": {"/users/{id}/photos": {"post": {
+			    "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer", "format": "int64"}}],
+			    "requestBody": {"required": true, "content": {"multipart/form-data": {"schema": {"type": "object",
+			      "properties": {
+			        "avatar": {"type": "string", "format": "binary"},
+			        "photos": {"type": "array", "items": {"type": "string", "format": "binary"}},
+			        "caption": {"type": "string", "description": "Caption of the photos."}},
+			      "required": ["avatar"]}}}},
+			    "responses": {"204": {"description": "No Content"}}}}}}`,
+		},
+		{
+			name: "query arrays and referenced parameters",
+			swagger: `{"swagger": "2.0", "info": {"title": ""},
+			  "paths": {"/users": {"parameters": [{"$ref": "#/parameters/Tenant"}], "get": {
+			    "parameters": [
+			      {"name": "tags", "in": "query", "type": "array", "items": {"type": "string"}, "collectionFormat": "multi"},
+			      {"name": "ids", "in": "query", "type": "array", "items": {"type": "integer"}},
+			      {"name": "limit", "in": "query", "type": "integer", "minimum": 1, "x-example": 20}],
+			    "responses": {"200": {"description": "OK"}}}}},
+			  "parameters": {"Tenant": {"name": "X-Tenant", "in": "header", "type": "string"}}}`,
+			expected: `{"openapi": "3.0.3", "info": {"title": ""},
+			  "paths": {"/users": {"parameters": [{"$ref": "#/components/parameters/Tenant"}], "get": {
+			    "parameters": [
+			      {"name": "tags", "in": "query", "style": "form", "explode": true, "schema": {"type": "array", "items": {"type": "string"}}},
+			      {"name": "ids", "in": "query", "style": "form", "explode": false, "schema": {"type": "array", "items": {"type": "integer"}}},
+			      {"name": "limit", "in": "query", "example": 20, "schema": {"type": "integer", "minimum": 1}}],
+			    "responses": {"200": {"description": "OK"}}}}},
+			  "components": {"parameters": {"Tenant": {"name": "X-Tenant", "in": "header", "schema": {"type": "string"}}}}}`,
+		},
+		{
+			name: "security",
+			swagger: `{"swagger": "2.0", "info": {"title": ""}, "paths": {}, "security": [{"basic": []}],
+			  "securityDefinitions": {
+			    "basic": {"type": "basic"},
+			    "key": {"type": "apiKey", "name": "X-Key", "in": "header"},
+			    "oauth": {"type": "oauth2", "flow": "accessCode", "authorizationUrl": "https://example.com/auth",
+			      "tokenUrl": "https://example.com/token", "scopes": {"read": "Read access"}}}}`,
+			expected: `{"openapi": "3.0.3", "info": {"title": ""}, "paths": {}, "security": [{"basic": []}],
+			  "components": {"securitySchemes": {
+			    "basic": {"type": "http", "scheme": "basic"},
+			    "key": {"type": "apiKey", "name": "X-Key", "in": "header"},
+			    "oauth": {"type": "oauth2", "flows": {"authorizationCode": {"authorizationUrl": "https://example.com/auth",
+			      "tokenUrl": "https://example.com/token", "scopes": {"read": "Read access"}}}}}}}`,
+		},
+		{
+			name: "discriminator",
+			swagger: `{"swagger": "2.0", "info": {"title": ""},
+			  "paths": {"/events": {"post": {
+			    "parameters": [{"name": "event", "in": "body", "schema": {"$ref": "#/definitions/Event"}}],
+			    "responses": {"200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Event"}}}}}}},
+			  "definitions": {
+			    "Event": {"type": "object", "discriminator": "kind", "required": ["kind"], "properties": {"kind": {"type": "string"}}},
+			    "CreatedEvent": {"allOf": [{"$ref": "#/definitions/Event"}, {"type": "object", "properties": {"id": {"type": "string"}}}],
+			      "x-discriminator-value": "created"},
+			    "DeletedEvent": {"allOf": [{"$ref": "#/definitions/Event"}]}}}`,
+			expected: `{"openapi": "3.0.3", "info": {"title": ""},
+			  "paths": {"/events": {"post": {
+			    "requestBody": {"content": {"application/json": {"schema": {
+			      "oneOf": [{"$ref": "#/components/schemas/DeletedEvent"}, {"$ref": "#/components/schemas/CreatedEvent"}],
+			      "discriminator": {"propertyName": "kind",
+			        "mapping": {"created": "#/components/schemas/CreatedEvent", "DeletedEvent": "#/components/schemas/DeletedEvent"}}}}}},
+			    "responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"type": "array", "items": {
+			      "oneOf": [{"$ref": "#/components/schemas/DeletedEvent"}, {"$ref": "#/components/schemas/CreatedEvent"}],
+			      "discriminator": {"propertyName": "kind",
+			        "mapping": {"created": "#/components/schemas/CreatedEvent", "DeletedEvent": "#/components/schemas/DeletedEvent"}}}}}}}}}}},
+			  "components": {"schemas": {
+			    "Event": {"type": "object", "required": ["kind"], "properties": {"kind": {"type": "string"}},
+			      "discriminator": {"propertyName": "kind",
+			        "mapping": {"created": "#/components/schemas/CreatedEvent", "DeletedEvent": "#/components/schemas/DeletedEvent"}}},
+			    "CreatedEvent": {"allOf": [{"$ref": "#/components/schemas/Event"}, {"type": "object", "properties": {"id": {"type": "string"}}}],
+			      "x-discriminator-value": "created"},
+			    "DeletedEvent": {"allOf": [{"$ref": "#/components/schemas/Event"}]}}}}`,
+		},
+		{
+			name: "nullable and write only properties",
+			swagger: `{"swagger": "2.0", "info": {"title": ""}, "paths": {},
+			  "definitions": {"User": {"type": "object", "properties": {
+			    "id": {"type": "string", "readOnly": true},
+			    "password": {"type": "string", "x-writeOnly": true},
+			    "manager": {"allOf": [{"$ref": "#/definitions/User"}], "x-nullable": true},
+			    "tags": {"type": "array", "items": {"type": "string", "x-nullable": true}}}}}}`,
+			expected: `{"openapi": "3.0.3", "info": {"title": ""}, "paths": {},
+			  "components": {"schemas": {"User": {"type": "object", "properties": {
+			    "id": {"type": "string", "readOnly": true},
+			    "password": {"type": "string", "writeOnly": true},
+			    "manager": {"allOf": [{"$ref": "#/components/schemas/User"}], "nullable": true},
+			    "tags": {"type": "array", "items": {"type": "string", "nullable": true}}}}}}}`,
+		},
+	}
+
+	for _, test := range tests {
+		swagger := &SwaggerObject{}
+		if err := json.Unmarshal([]byte(test.swagger), swagger); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		var expected interface{}
+		if err := json.Unmarshal([]byte(test.expected), &expected); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		openapi, err := ConvertToOpenAPI3(swagger)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if !JsonEqual(openapi, expected) {
+			actual, _ := json.MarshalIndent(openapi, "", "  ")
+			t.Errorf("%s: unexpected document\n%s", test.name, actual)
+		}
+	}
+}
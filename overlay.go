@@ -0,0 +1,233 @@
+package mswagger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// OverlayObject is an OpenAPI Overlay document, see https://spec.openapis.org/overlay/v1.0.0.
+type OverlayObject struct {
+	Overlay string          `json:"overlay"`
+	Info    *InfoObject     `json:"info,omitempty"`
+	Extends string          `json:"extends,omitempty"`
+	Actions []*ActionObject `json:"actions"`
+}
+
+// ActionObject updates or removes the values selected by the JSONPath Target.
+type ActionObject struct {
+	Target      string      `json:"target"`
+	Description string      `json:"description,omitempty"`
+	Update      interface{} `json:"update,omitempty"`
+	Remove      bool        `json:"remove,omitempty"`
+}
+
+// removedValue marks values deleted by an action until they are swept.
+type removedValue struct{}
+
+// ApplyOverlayFiles applies the overlay or merge patch files in order.
+func ApplyOverlayFiles(swagger *SwaggerObject, filePaths []string) error {
+	for _, filePath := range filePaths {
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		if err := ApplyOverlay(swagger, data); err != nil {
+			return fmt.Errorf("Can not apply overlay %s: %v", filePath, err)
+		}
+	}
+	return nil
+}
+
+// ApplyOverlay applies data to swagger. data is either an OpenAPI Overlay
+// document, recognized by its "overlay" property, or a JSON merge patch (RFC 7396).
+func ApplyOverlay(swagger *SwaggerObject, data []byte) error {
+	var patch interface{}
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return err
+	}
+
+	document, err := swaggerDocument(swagger)
+	if err != nil {
+		return err
+	}
+
+	if object, ok := patch.(map[string]interface{}); ok && object["overlay"] != nil {
+		overlay := &OverlayObject{}
+		if err := json.Unmarshal(data, overlay); err != nil {
+			return err
+		}
+		if document, err = applyOverlayActions(document, overlay.Actions); err != nil {
+			return err
+		}
+	} else {
+		document = MergePatch(document, patch)
+	}
+
+	output, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+	*swagger = SwaggerObject{}
+	return json.Unmarshal(output, swagger)
+}
+
+func swaggerDocument(swagger *SwaggerObject) (interface{}, error) {
+	output, err := json.Marshal(swagger)
+	if err != nil {
+		return nil, err
+	}
+	var document interface{}
+	err = json.Unmarshal(output, &document)
+	return document, err
+}
+
+func applyOverlayActions(document interface{}, actions []*ActionObject) (interface{}, error) {
+	for i, action := range actions {
+		paths, err := JsonPathQuery(document, action.Target)
+		if err != nil {
+			return nil, fmt.Errorf("action %d: %v", i, err)
+		}
+		for _, path := range paths {
+			if action.Remove {
+				if len(path) == 0 {
+					return nil, fmt.Errorf("action %d: can not remove the root", i)
+				}
+				document = setJsonValue(document, path, removedValue{})
+				continue
+			}
+			if action.Update == nil {
+				continue
+			}
+			// Every target gets its own copy of the update
+			var update interface{}
+			if output, err := json.Marshal(action.Update); err != nil {
+				return nil, err
+			} else if err := json.Unmarshal(output, &update); err != nil {
+				return nil, err
+			}
+
+			target := getJsonValue(document, path)
+			switch target.(type) {
+			case map[string]interface{}:
+				if _, ok := update.(map[string]interface{}); !ok {
+					return nil, fmt.Errorf("action %d: update of the object %s must be an object", i, action.Target)
+				}
+				target = mergeJsonValues(target, update)
+			case []interface{}:
+				target = append(target.([]interface{}), update)
+			default:
+				target = update
+			}
+			document = setJsonValue(document, path, target)
+		}
+		document = sweepRemovedValues(document)
+	}
+	return document, nil
+}
+
+// mergeJsonValues merges update into target recursively: objects are merged,
+// arrays are concatenated and other values are replaced.
+func mergeJsonValues(target, update interface{}) interface{} {
+	switch updateValue := update.(type) {
+	case map[string]interface{}:
+		targetObject, ok := target.(map[string]interface{})
+		if !ok {
+			return update
+		}
+		for key, value := range updateValue {
+			if existing, ok := targetObject[key]; ok {
+				targetObject[key] = mergeJsonValues(existing, value)
+			} else {
+				targetObject[key] = value
+			}
+		}
+		return targetObject
+	case []interface{}:
+		if targetArray, ok := target.([]interface{}); ok {
+			return append(targetArray, updateValue...)
+		}
+	}
+	return update
+}
+
+// MergePatch applies a JSON merge patch (RFC 7396) to a decoded json document.
+func MergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = MergePatch(targetObject[key], value)
+		}
+	}
+	return targetObject
+}
+
+func getJsonValue(document interface{}, path []interface{}) interface{} {
+	for _, key := range path {
+		switch k := key.(type) {
+		case string:
+			document = document.(map[string]interface{})[k]
+		case int:
+			document = document.([]interface{})[k]
+		}
+	}
+	return document
+}
+
+// setJsonValue sets the value at path and returns the possibly new document.
+func setJsonValue(document interface{}, path []interface{}, value interface{}) interface{} {
+	if len(path) == 0 {
+		return value
+	}
+	switch k := path[0].(type) {
+	case string:
+		object := document.(map[string]interface{})
+		object[k] = setJsonValue(object[k], path[1:], value)
+	case int:
+		array := document.([]interface{})
+		array[k] = setJsonValue(array[k], path[1:], value)
+	}
+	return document
+}
+
+func sweepRemovedValues(document interface{}) interface{} {
+	switch value := document.(type) {
+	case map[string]interface{}:
+		for key, element := range value {
+			if _, ok := element.(removedValue); ok {
+				delete(value, key)
+			} else {
+				value[key] = sweepRemovedValues(element)
+			}
+		}
+	case []interface{}:
+		result := value[:0]
+		for _, element := range value {
+			if _, ok := element.(removedValue); !ok {
+				result = append(result, sweepRemovedValues(element))
+			}
+		}
+		return result
+	}
+	return document
+}
+
+func splitOverlays(overlays string) []string {
+	var filePaths []string
+	for _, filePath := range strings.Split(overlays, ",") {
+		if filePath = strings.TrimSpace(filePath); filePath != "" {
+			filePaths = append(filePaths, filePath)
+		}
+	}
+	return filePaths
+}
@@ -0,0 +1,86 @@
+package mswagger
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestApplyOverlay(t *testing.T) {
+	swagger := `{"swagger": "2.0", "info": {"title": "Users", "version": "1.0"},
+	  "paths": {"/users": {"get": {"tags": ["users"], "parameters": [
+	    {"name": "page", "in": "query", "type": "integer"},
+	    {"name": "X-Debug", "in": "header", "type": "string"}],
+	    "responses": {"200": {"description": "OK"}}}}}}`
+	tests := []struct {
+		name     string
+		overlay  string
+		expected string
+	}{
+		{
+			name: "update objects and arrays",
+			overlay: `{"overlay": "1.0.0", "info": {"title": "Docs", "version": "1"}, "actions": [
+			  {"target": "$.info", "update": {"description": "User service."}},
+			  {"target": "$.paths['/users'].get.tags", "update": "public"},
+			  {"target": "$.paths..parameters[?(@.name == 'page')]", "update": {"minimum": 1}}]}`,
+			expected: `{"swagger": "2.0", "info": {"title": "Users", "version": "1.0", "description": "User service."},
+			  "paths": {"/users": {"get": {"tags": ["users", "public"], "parameters": [
+			    {"name": "page", "in": "query", "type": "integer", "minimum": 1},
+			    {"name": "X-Debug", "in": "header", "type": "string"}],
+			    "responses": {"200": {"description": "OK"}}}}}}`,
+		},
+		{
+			name: "remove",
+			overlay: `{"overlay": "1.0.0", "info": {"title": "Docs", "version": "1"}, "actions": [
+			  {"target": "$..parameters[?(@.in == 'header')]", "remove": true}]}`,
+			expected: `{"swagger": "2.0", "info": {"title": "Users", "version": "1.0"},
+			  "paths": {"/users": {"get": {"tags": ["users"], "parameters": [
+			    {"name": "page", "in": "query", "type": "integer"}],
+			    "responses": {"200": {"description": "OK"}}}}}}`,
+		},
+		{
+			name:    "merge patch",
+			overlay: `{"host": "api.example.com", "info": {"version": null}, "paths": {"/users": {"get": {"tags": ["admin"]}}}}`,
+			expected: `{"swagger": "2.0", "host": "api.example.com", "info": {"title": "Users"},
+			  "paths": {"/users": {"get": {"tags": ["admin"], "parameters": [
+			    {"name": "page", "in": "query", "type": "integer"},
+			    {"name": "X-Debug", "in": "header", "type": "string"}],
+			    "responses": {"200": {"description": "OK"}}}}}}`,
+		},
+	}
+
+	for _, test := range tests {
+		document := &SwaggerObject{}
+		if err := json.Unmarshal([]byte(swagger), document); err != nil {
+			t.Fatal(err)
+		}
+		if err := ApplyOverlay(document, []byte(test.overlay)); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		var expected interface{}
+		if err := json.Unmarshal([]byte(test.expected), &expected); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !JsonEqual(document, expected) {
+			actual, _ := json.Marshal(document)
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestApplyOverlayErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		overlay string
+	}{
+		{name: "invalid json path", overlay: `{"overlay": "1.0.0", "actions": [{"target": "info", "update": {}}]}`},
+		{name: "remove the root", overlay: `{"overlay": "1.0.0", "actions": [{"target": "$", "remove": true}]}`},
+		{name: "update an object with a value", overlay: `{"overlay": "1.0.0", "actions": [{"target": "$.info", "update": "x"}]}`},
+	}
+
+	for _, test := range tests {
+		if err := ApplyOverlay(&SwaggerObject{Info: &InfoObject{}}, []byte(test.overlay)); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
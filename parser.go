@@ -7,6 +7,7 @@ import (
 	goparser "go/parser"
 	"go/token"
 	"log"
+	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -22,6 +23,7 @@ type Parser struct {
 	ApiPackage                        string
 	Swagger                           *SwaggerObject
 	PackagesCache                     map[string]map[string]*ast.Package
+	receiverMethodsCache              map[string]map[string]map[string]bool
 	CurrentPackage                    string
 	TypeDefinitions                   map[string]map[string]*ast.TypeSpec
 	PackagePathCache                  map[string]string
@@ -31,16 +33,62 @@ type Parser struct {
 	Ignore                            string
 	IsController                      func(*ast.FuncDecl, string) bool
 	TypesImplementingMarshalInterface map[string]string
+	// Infer the path and method of controllers without @Router from route registrations
+	InferRoutes bool
+	Routes      []*Route
+	// Infer request bodies, parameters and responses of controllers from their code
+	InferHandlers bool
+	Diagnostics   []*Diagnostic
+	// Render embedded structs as allOf of their definitions instead of copying their properties
+	EmbeddedAllOf bool
+	// Require the fields without omitempty and make pointer fields nullable
+	OmitemptyPolicy bool
+	// Swagger types of the basic go types
+	TypeMappings TypeMappings
+	// Basic types of the definitions of typedefs like type Status string
+	typeDefTranslations map[string]string
+	// Swagger types of marshalers and of types annotated with @SwaggerType
+	swaggerTypes TypeMappings
+	// Definition names of the parsed models
+	modelNamesPackageNames map[string]string
+	// GOPATH the packages are looked up in, $GOPATH if it is empty
+	Gopath string
+}
+
+// parseError aborts parsing, ParseSwagger returns it.
+type parseError struct {
+	err error
+}
+
+// fatalf aborts parsing with the error format.
+func (parser *Parser) fatalf(format string, v ...interface{}) {
+	panic(parseError{fmt.Errorf(strings.TrimSuffix(format, "\n"), v...)})
+}
+
+// recoverParseError sets err to the error parsing was aborted with.
+func recoverParseError(err *error) {
+	if r := recover(); r != nil {
+		aborted, ok := r.(parseError)
+		if !ok {
+			panic(r)
+		}
+		*err = aborted.err
+	}
 }
 
 func NewParser() *Parser {
 	return &Parser{
 		Swagger:                           &SwaggerObject{},
 		PackagesCache:                     make(map[string]map[string]*ast.Package),
+		receiverMethodsCache:              make(map[string]map[string]map[string]bool),
 		TypeDefinitions:                   make(map[string]map[string]*ast.TypeSpec),
 		PackagePathCache:                  make(map[string]string),
 		PackageImports:                    make(map[string]map[string][]string),
 		TypesImplementingMarshalInterface: make(map[string]string),
+		TypeMappings:                      DefaultTypeMappings(),
+		typeDefTranslations:               make(map[string]string),
+		swaggerTypes:                      make(TypeMappings),
+		modelNamesPackageNames:            make(map[string]string),
 	}
 }
 
@@ -50,7 +98,7 @@ func (parser *Parser) ParseGeneralSwaggerInfo(mainApiFile string) {
 	fileSet := token.NewFileSet()
 	fileTree, err := goparser.ParseFile(fileSet, mainApiFile, nil, goparser.ParseComments)
 	if err != nil {
-		log.Fatalf("Can not parse general API information: %v\n", err)
+		parser.fatalf("Can not parse general API information: %v\n", err)
 	}
 
 	parser.BasePath = ""
@@ -91,6 +139,13 @@ func (parser *Parser) ParseGeneralSwaggerInfo(mainApiFile string) {
 			}
 		}
 	}
+	// Empty contact and license objects are not valid
+	if contact := parser.Swagger.Info.Contact; contact.Name == "" && contact.URL == "" && contact.Email == "" {
+		parser.Swagger.Info.Contact = nil
+	}
+	if parser.Swagger.Info.License.Name == "" {
+		parser.Swagger.Info.License = nil
+	}
 }
 
 // Parase apis info
@@ -99,6 +154,12 @@ func (parser *Parser) ParseApi(packageNames string) {
 	for _, packageName := range packages {
 		parser.ParseTypeDefinitions(packageName)
 	}
+	if parser.InferRoutes {
+		for _, packageName := range packages {
+			parser.ParseRoutes(packageName)
+		}
+		parser.resolveRouteReceivers(packages)
+	}
 	for _, packageName := range packages {
 		parser.ParseApiDescription(packageName)
 	}
@@ -122,6 +183,10 @@ func (parser *Parser) ParseTypeDefinitions(packageName string) {
 				if generalDeclaration, ok := astDeclaration.(*ast.GenDecl); ok && generalDeclaration.Tok == token.TYPE {
 					for _, astSpec := range generalDeclaration.Specs {
 						if typeSpec, ok := astSpec.(*ast.TypeSpec); ok {
+							// The comments of a single type declaration belong to the type
+							if typeSpec.Doc == nil && len(generalDeclaration.Specs) == 1 {
+								typeSpec.Doc = generalDeclaration.Doc
+							}
 							parser.TypeDefinitions[pkgRealPath][typeSpec.Name.String()] = typeSpec
 						}
 					}
@@ -163,8 +228,7 @@ func (parser *Parser) ParseImportStatements(packageName string) map[string]bool
 					if astImport.Name != nil && astImport.Name.Name != "." && astImport.Name.Name != "_" {
 						importedPackageAlias = astImport.Name.Name
 					} else {
-						importPath := strings.Split(importedPackageName, "/")
-						importedPackageAlias = importPath[len(importPath)-1]
+						importedPackageAlias = importName(importedPackageName)
 					}
 
 					isExists := false
@@ -184,6 +248,21 @@ func (parser *Parser) ParseImportStatements(packageName string) map[string]bool
 	return imports
 }
 
+var goVersionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// importName returns the name a package is imported as without alias, like chi for
+// github.com/go-chi/chi/v5, null for gopkg.in/guregu/null.v4 and uuid for
+// github.com/satori/go.uuid.
+func importName(importPath string) string {
+	parts := strings.Split(importPath, "/")
+	name := parts[len(parts)-1]
+	if goVersionSuffix.MatchString(name) && len(parts) > 1 {
+		name = parts[len(parts)-2]
+	}
+	name = strings.TrimPrefix(strings.TrimPrefix(name, "go-"), "go.")
+	return strings.Split(name, ".")[0]
+}
+
 func (parser *Parser) ParseApiDescription(packageName string) {
 	parser.CurrentPackage = packageName
 	pkgRealPath := parser.GetRealPackagePath(packageName)
@@ -203,6 +282,15 @@ func (parser *Parser) ParseApiDescription(packageName string) {
 								}
 							}
 						}
+						if parser.InferRoutes && !operation.routed && isAnnotated(astDeclaration.Doc) {
+							for _, route := range parser.FindRoutes(packageName, astDeclaration) {
+								operation.AddRoute(route.Path, route.Method)
+							}
+						}
+						if parser.InferHandlers && isAnnotated(astDeclaration.Doc) {
+							operation.InferFromHandler(astDeclaration, fileImports(astFile))
+						}
+						operation.SetDefaultConsumes()
 						// if operation.Path != "" {
 						// 	// parser.AddOperation(operation)
 						// }
@@ -225,9 +313,12 @@ func (parser *Parser) CheckRealPackagePath(packagePath string) string {
 		return cachedResult
 	}
 
-	gopath := os.Getenv("GOPATH")
+	gopath := parser.Gopath
+	if gopath == "" {
+		gopath = os.Getenv("GOPATH")
+	}
 	if gopath == "" {
-		log.Fatalf("Please, set $GOPATH environment variable\n")
+		parser.fatalf("Please, set $GOPATH environment variable\n")
 	}
 
 	// first check GOPATH
@@ -251,7 +342,7 @@ func (parser *Parser) CheckRealPackagePath(packagePath string) string {
 	if pkgRealpath == "" {
 		goroot := filepath.Clean(runtime.GOROOT())
 		if goroot == "" {
-			log.Fatalf("Please, set $GOROOT environment variable\n")
+			parser.fatalf("Please, set $GOROOT environment variable\n")
 		}
 		if evalutedPath, err := filepath.EvalSymlinks(filepath.Join(goroot, "src", packagePath)); err == nil {
 			if _, err := os.Stat(evalutedPath); err == nil {
@@ -296,7 +387,7 @@ func (parser *Parser) GetPackageAst(packagePath string) map[string]*ast.Package
 
 		astPackages, err := goparser.ParseDir(fileSet, packagePath, ParserFileFilter, goparser.ParseComments)
 		if err != nil {
-			log.Fatalf("Parse of %s pkg cause error: %s\n", packagePath, err)
+			parser.fatalf("Parse of %s pkg cause error: %s\n", packagePath, err)
 		}
 		parser.PackagesCache[packagePath] = astPackages
 		return astPackages
@@ -357,7 +448,7 @@ func (parser *Parser) FindModelDefinition(modelName string, currentPackage strin
 	if len(modelNameParts) == 1 {
 		modelPackage = currentPackage
 		if model = parser.GetModelDefinition(modelName, currentPackage); model == nil {
-			log.Fatalf("Can not find definition of %s model. Current package %s", modelName, currentPackage)
+			parser.fatalf("Can not find definition of %s model. Current package %s", modelName, currentPackage)
 		}
 	} else {
 		//first try to assume what name is absolute
@@ -369,15 +460,15 @@ func (parser *Parser) FindModelDefinition(modelName string, currentPackage strin
 
 			//can not get model by absolute name.
 			if len(modelNameParts) > 2 {
-				log.Fatalf("Can not find definition of %s model. Name looks like absolute, but model not found in %s package", modelNameFromPath, absolutePackageName)
+				parser.fatalf("Can not find definition of %s model. Name looks like absolute, but model not found in %s package", modelNameFromPath, absolutePackageName)
 			}
 
 			// lets try to find it in imported packages
 			pkgRealPath := parser.CheckRealPackagePath(currentPackage)
 			if imports, ok := parser.PackageImports[pkgRealPath]; !ok {
-				log.Fatalf("Can not find definition of %s model. Package %s dont import anything", modelNameFromPath, pkgRealPath)
+				parser.fatalf("Can not find definition of %s model. Package %s dont import anything", modelNameFromPath, pkgRealPath)
 			} else if relativePackage, ok := imports[modelNameParts[0]]; !ok {
-				log.Fatalf("Package %s is not imported to %s, Imported: %#v\n", modelNameParts[0], currentPackage, imports)
+				parser.fatalf("Package %s is not imported to %s, Imported: %#v\n", modelNameParts[0], currentPackage, imports)
 			} else {
 				var modelFound bool
 
@@ -391,7 +482,7 @@ func (parser *Parser) FindModelDefinition(modelName string, currentPackage strin
 				}
 
 				if !modelFound {
-					log.Fatalf("Can not find definition of %s model in package %s", modelNameFromPath, relativePackage)
+					parser.fatalf("Can not find definition of %s model in package %s", modelNameFromPath, relativePackage)
 				}
 			}
 		}
@@ -403,7 +494,7 @@ func (parser *Parser) isIgnoredPackage(packageName string) bool {
 	r, _ := regexp.Compile("appengine+")
 	matched, err := regexp.MatchString(parser.Ignore, packageName)
 	if err != nil {
-		log.Fatalf("The -ignore argument is not a valid regular expression: %v\n", err)
+		parser.fatalf("The -ignore argument is not a valid regular expression: %v\n", err)
 	}
 	return packageName == "C" || r.MatchString(packageName) || matched
 }
@@ -501,93 +592,102 @@ func (operation *OperationObject) ParseRouterComment(commentLine string) error {
 		return fmt.Errorf("Can not parse router comment \"%s\", skipped.", commentLine)
 	}
 
-	if _, ok := operation.parser.Swagger.Paths[matches[1]]; !ok {
-		operation.parser.Swagger.Paths[matches[1]] = &PathItemObject{}
-	}
+	operation.AddRoute(matches[1], matches[2])
 
-	switch strings.ToUpper(matches[2]) {
-	case "GET":
-		if operation.parser.Swagger.Paths[matches[1]].Get == nil {
-			operation.parser.Swagger.Paths[matches[1]].Get = operation
-		}
-	case "POST":
-		if operation.parser.Swagger.Paths[matches[1]].Post == nil {
-			operation.parser.Swagger.Paths[matches[1]].Post = operation
-		}
-	case "PATCH":
-		if operation.parser.Swagger.Paths[matches[1]].Patch == nil {
-			operation.parser.Swagger.Paths[matches[1]].Patch = operation
-		}
-	case "PUT":
-		if operation.parser.Swagger.Paths[matches[1]].Put == nil {
-			operation.parser.Swagger.Paths[matches[1]].Put = operation
-		}
-	case "DELETE":
-		if operation.parser.Swagger.Paths[matches[1]].Delete == nil {
-			operation.parser.Swagger.Paths[matches[1]].Delete = operation
-		}
-	case "OPTIONS":
-		if operation.parser.Swagger.Paths[matches[1]].Options == nil {
-			operation.parser.Swagger.Paths[matches[1]].Options = operation
-		}
-	case "HEAD":
-		if operation.parser.Swagger.Paths[matches[1]].Head == nil {
-			operation.parser.Swagger.Paths[matches[1]].Head = operation
-		}
+	return nil
+}
+
+// AddRoute adds operation to the paths of the swagger object, unless path
+// already has an operation for method.
+func (operation *OperationObject) AddRoute(path, method string) {
+	if _, ok := operation.parser.Swagger.Paths[path]; !ok {
+		operation.parser.Swagger.Paths[path] = &PathItemObject{}
 	}
 
-	return nil
+	method = strings.ToUpper(method)
+	if !IsInStringList(Methods, method) {
+		return
+	}
+	if _, ok := operation.parser.Swagger.Paths[path].Operations()[method]; !ok {
+		operation.parser.Swagger.Paths[path].SetOperation(method, operation)
+	}
+	operation.routed = true
 }
 
+// ParseResponseComment parses responses like 200 {object} User "description",
+// 200 {array} string "description", 200 {map} User "description", 200 {object} [][]User "description",
+// 200 {object} Envelope{data=[]User} "description", 200 {file} "description",
+// 204 "description" and default {object} Error "description".
 func (operation *OperationObject) ParseResponseComment(commentLine string) error {
-	re := regexp.MustCompile(`([\d]+)[\s]+([\w\{\}]+)[\s]+([\w\-\.\/]+)[^"]*(.*)?`)
+	re := regexp.MustCompile(`^([\w]+)(?:[\s]+(\{\w+\}))?(?:[\s]+([^\s"{]+(?:\{[^"]*\})?))?[\s]*(.*)$`)
 	var matches []string
 
-	if matches = re.FindStringSubmatch(commentLine); len(matches) != 5 {
+	if matches = re.FindStringSubmatch(strings.TrimSpace(commentLine)); len(matches) != 5 {
 		return fmt.Errorf("Can not parse response comment \"%s\", skipped.", commentLine)
 	}
 
-	var response *ResponseObject
-	var code int
-	if code, err := strconv.Atoi(matches[1]); err != nil {
-		return errors.New("Success http code must be int")
-	} else {
-		operation.Responses[fmt.Sprint(code)] = &ResponseObject{
-			Schema: &SchemaObject{},
+	code := matches[1]
+	description := strings.Trim(strings.TrimSpace(matches[4]), "\"")
+	if code == "default" {
+		if description == "" {
+			description = "Default response"
 		}
-		response = operation.Responses[fmt.Sprint(code)]
+	} else if statusCode, err := strconv.Atoi(code); err != nil {
+		return errors.New("Success http code must be int")
+	} else if description == "" {
+		description = statusDescription(statusCode)
 	}
-	response.Description = strings.Trim(matches[4], "\"")
 
-	typeName, err := operation.registerType(matches[3])
+	schema, err := operation.responseSchema(strings.Trim(matches[2], "{}"), matches[3])
 	if err != nil {
 		return err
 	}
-	//
-	// response.Schema.Type = strings.Trim(matches[2], "{}")
-	if _, ok := operation.parser.Swagger.Definitions[typeName]; ok {
-		response.Schema.Ref = "#/definitions/" + typeName
-	} else {
-		response.Schema.Type = strings.Trim(matches[2], "{}")
-		// response.Schema.Properties = operation.parser.Swagger.Definitions[typeName].Properties
+
+	operation.Responses[code] = &ResponseObject{
+		Description: description,
+		Schema:      schema,
 	}
 
-	if code == 200 {
-		if matches[2] == "{array}" {
-			// operation.SetItemsType(typeName)
-			response.Schema.Type = "array"
-			response.Schema.Items = &ReferenceObject{
-				Ref: "#/definitions/" + typeName,
-			}
-		} else {
-			response.Schema.Type = typeName
-		}
+	return nil
+}
+
+// statusDescription returns the description of responses with the status code
+// when none is given, which is required even for unknown codes.
+func statusDescription(code int) string {
+	if text := http.StatusText(code); text != "" {
+		return text
 	}
+	return fmt.Sprintf("Status %d", code)
+}
 
-	// output, err := json.MarshalIndent(response, "", "  ")
-	// fmt.Println(string(output))
+// responseSchema returns the schema of a response of kind like object, array, map or file
+// and typeName, nil for responses without body.
+func (operation *OperationObject) responseSchema(kind, typeName string) (*SchemaObject, error) {
+	if kind == "file" {
+		return &SchemaObject{Type: "file"}, nil
+	}
+	if typeName == "" {
+		switch kind {
+		case "", "object", "array":
+			return nil, nil
+		case "map":
+			return &SchemaObject{Type: "object"}, nil
+		}
+		// {string} "description"
+		typeName = kind
+	}
 
-	return nil
+	schema, err := operation.typeSchema(typeName)
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case "array":
+		schema = &SchemaObject{Type: "array", Items: &SchemaObjectOrArray{Schema: schema}}
+	case "map":
+		schema = &SchemaObject{Type: "object", AdditionalProperties: &BoolOrSchemaObject{Schema: schema}}
+	}
+	return schema, nil
 }
 
 type Parameter struct {
@@ -604,24 +704,33 @@ type Parameter struct {
 }
 
 func (operation *OperationObject) ParseParamComment(commentLine string) error {
-	swaggerParameter := ParameterObject{}
+	swaggerParameter := &ParameterObject{}
 	paramString := commentLine
 
-	re := regexp.MustCompile(`([-\w]+)[\s]+([\w]+)[\s]+([\w.]+)[\s]+([\w]+)[\s]+"([^"]+)"`)
+	re := regexp.MustCompile(`([-\w]+)[\s]+([\w]+)[\s]+([\[\]\w.]+)[\s]+([\w]+)[\s]+"([^"]+)"`)
 
 	if matches := re.FindStringSubmatch(paramString); len(matches) != 6 {
 		return fmt.Errorf("Can not parse param comment \"%s\", skipped.", paramString)
+	} else if matches[1] == "_" && matches[2] != "body" {
+		return operation.ParseParamStruct(matches[2], matches[3], matches[5])
 	} else {
-		typeName, err := operation.registerType(matches[3])
+		isArray := strings.HasPrefix(matches[3], "[]")
+		typeName := strings.TrimPrefix(matches[3], "[]")
+		if typeName == "file" && matches[2] != "formData" {
+			return fmt.Errorf("File param %s must be in formData.", matches[1])
+		}
+
+		typeName, err := operation.registerType(typeName)
 		if err != nil {
 			return err
 		}
 
 		swaggerParameter.Name = matches[1]
 		swaggerParameter.In = matches[2]
-		if IsBasicTypeSwaggerType(typeName) {
-			swaggerParameter.Type = basicTypesSwaggerTypes[typeName]
-			swaggerParameter.Format = basicTypesSwaggerFormats[typeName]
+		if mappings := operation.parser.TypeMappings; mappings.IsBasicTypeSwaggerType(typeName) {
+			swaggerParameter.Type = mappings.Type(typeName)
+			swaggerParameter.Format = mappings.Format(typeName)
+			swaggerParameter.Minimum = mappings.Minimum(typeName)
 		} else {
 			if _, ok := operation.parser.Swagger.Definitions[typeName]; ok {
 				// swaggerParameter.Ref = "#/definitions/" + typeName
@@ -632,10 +741,281 @@ func (operation *OperationObject) ParseParamComment(commentLine string) error {
 				swaggerParameter.Type = typeName
 			}
 		}
+		if isArray {
+			swaggerParameter.SetArray()
+		}
 		requiredText := strings.ToLower(matches[4])
 		swaggerParameter.Required = (requiredText == "true" || requiredText == "required")
 		swaggerParameter.Description = matches[5]
 
+		// Optional attributes like enums(a,b) default(10) follow the description
+		attributes := paramString[re.FindStringIndex(paramString)[1]:]
+		if err := swaggerParameter.ParseAttributes(attributes); err != nil {
+			return err
+		}
+
+		operation.Parameters = append(operation.Parameters, swaggerParameter)
+	}
+
+	return nil
+}
+
+// SetArray turns the parameter into an array of its type.
+func (swaggerParameter *ParameterObject) SetArray() {
+	if swaggerParameter.In == "body" {
+		items := swaggerParameter.Schema
+		if items == nil {
+			items = &SchemaObject{Type: swaggerParameter.Type, Format: swaggerParameter.Format, Minimum: swaggerParameter.Minimum}
+		}
+		swaggerParameter.Type, swaggerParameter.Format, swaggerParameter.Minimum = "", "", nil
+		swaggerParameter.Schema = &SchemaObject{Type: "array", Items: &SchemaObjectOrArray{Schema: items}}
+		return
+	}
+
+	swaggerParameter.Items = &ItemsObject{Type: swaggerParameter.Type, Format: swaggerParameter.Format, Minimum: swaggerParameter.Minimum}
+	if swaggerParameter.Type == "file" {
+		// Items of swagger 2.0 can not be files, the binary strings are the closest
+		swaggerParameter.Items = &ItemsObject{Type: "string", Format: "binary"}
+	}
+	swaggerParameter.Type, swaggerParameter.Format, swaggerParameter.Minimum = "array", "", nil
+	if swaggerParameter.In == "query" || swaggerParameter.In == "formData" {
+		// Repeated keys like ?tag=a&tag=b and several files of one field
+		swaggerParameter.CollectionFormat = "multi"
+	}
+}
+
+// ParseAttributes sets the attributes enums(a,b,c), default(10), minimum(1), maximum(100),
+// minlength(1), maxlength(64), pattern(^[a-z]+$), collectionFormat(multi), format(uuid)
+// and example(42) of the parameter. They apply to the items of array parameters,
+// except default, example and collectionFormat.
+func (swaggerParameter *ParameterObject) ParseAttributes(attributes string) error {
+	for {
+		attributes = strings.TrimSpace(attributes)
+		if attributes == "" {
+			return nil
+		}
+
+		start := strings.Index(attributes, "(")
+		if start == -1 {
+			return fmt.Errorf("Can not parse param attributes \"%s\" of %s.", attributes, swaggerParameter.Name)
+		}
+		// Values like pattern(^(a|b)$) can contain parentheses
+		depth, end := 0, -1
+		for i := start; i < len(attributes) && end == -1; i++ {
+			switch attributes[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+				if depth == 0 {
+					end = i
+				}
+			}
+		}
+		if end == -1 {
+			return fmt.Errorf("Missing ) in param attributes \"%s\" of %s.", attributes, swaggerParameter.Name)
+		}
+
+		name := strings.ToLower(strings.TrimSpace(attributes[:start]))
+		value := attributes[start+1 : end]
+		attributes = attributes[end+1:]
+		if err := swaggerParameter.setAttribute(name, value); err != nil {
+			return err
+		}
+	}
+}
+
+func (swaggerParameter *ParameterObject) setAttribute(name, value string) error {
+	if swaggerParameter.In == "body" {
+		return fmt.Errorf("Param %s is in body, the attribute %s belongs in the schema.", swaggerParameter.Name, name)
+	}
+
+	// The attributes of the values apply to the items of arrays
+	items := swaggerParameter.Items
+	if items == nil {
+		items = &ItemsObject{
+			Type:      swaggerParameter.Type,
+			Format:    swaggerParameter.Format,
+			Enum:      swaggerParameter.Enum,
+			Minimum:   swaggerParameter.Minimum,
+			Maximum:   swaggerParameter.Maximum,
+			MinLength: swaggerParameter.MinLength,
+			MaxLength: swaggerParameter.MaxLength,
+			Pattern:   swaggerParameter.Pattern,
+		}
+		defer func() {
+			swaggerParameter.Format, swaggerParameter.Enum = items.Format, items.Enum
+			swaggerParameter.Minimum, swaggerParameter.Maximum = items.Minimum, items.Maximum
+			swaggerParameter.MinLength, swaggerParameter.MaxLength = items.MinLength, items.MaxLength
+			swaggerParameter.Pattern = items.Pattern
+		}()
+	}
+
+	switch name {
+	case "enums", "enum":
+		items.Enum = nil
+		for _, v := range strings.Split(value, ",") {
+			enum, err := paramValue(items.Type, strings.TrimSpace(v))
+			if err != nil {
+				return fmt.Errorf("Can not parse enums(%s) of param %s: %v", value, swaggerParameter.Name, err)
+			}
+			items.Enum = append(items.Enum, enum)
+		}
+	case "minimum", "maximum":
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return fmt.Errorf("Can not parse %s(%s) of param %s: %v", name, value, swaggerParameter.Name, err)
+		}
+		if name == "minimum" {
+			items.Minimum = &f
+		} else {
+			items.Maximum = &f
+		}
+	case "minlength", "maxlength":
+		i, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return fmt.Errorf("Can not parse %s(%s) of param %s: %v", name, value, swaggerParameter.Name, err)
+		}
+		if name == "minlength" {
+			items.MinLength = &i
+		} else {
+			items.MaxLength = &i
+		}
+	case "pattern":
+		if _, err := regexp.Compile(value); err != nil {
+			return fmt.Errorf("Can not parse pattern(%s) of param %s: %v", value, swaggerParameter.Name, err)
+		}
+		items.Pattern = value
+	case "format":
+		items.Format = strings.TrimSpace(value)
+	case "collectionformat":
+		collectionFormat := strings.TrimSpace(value)
+		if !IsInStringList([]string{"csv", "ssv", "tsv", "pipes", "multi"}, collectionFormat) {
+			return fmt.Errorf("Unknown collectionFormat(%s) of param %s.", value, swaggerParameter.Name)
+		}
+		if collectionFormat == "multi" && swaggerParameter.In != "query" && swaggerParameter.In != "formData" {
+			return fmt.Errorf("Param %s is in %s, collectionFormat(multi) is only allowed in query and formData.", swaggerParameter.Name, swaggerParameter.In)
+		}
+		swaggerParameter.CollectionFormat = collectionFormat
+	case "default", "example":
+		v, err := swaggerParameter.value(value)
+		if err != nil {
+			return fmt.Errorf("Can not parse %s(%s) of param %s: %v", name, value, swaggerParameter.Name, err)
+		}
+		if name == "default" {
+			swaggerParameter.Default = v
+		} else {
+			// Parameters of swagger 2.0 have no example
+			if swaggerParameter.Extensions == nil {
+				swaggerParameter.Extensions = Extensions{}
+			}
+			swaggerParameter.Extensions["x-example"] = v
+		}
+	default:
+		return fmt.Errorf("Unknown attribute %s(%s) of param %s.", name, value, swaggerParameter.Name)
+	}
+
+	return nil
+}
+
+// value converts text to the type of the parameter, arrays are comma separated.
+func (swaggerParameter *ParameterObject) value(text string) (interface{}, error) {
+	if swaggerParameter.Items == nil {
+		return paramValue(swaggerParameter.Type, strings.TrimSpace(text))
+	}
+	values := []interface{}{}
+	for _, v := range strings.Split(text, ",") {
+		item, err := paramValue(swaggerParameter.Items.Type, strings.TrimSpace(v))
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, item)
+	}
+	return values, nil
+}
+
+// paramValue converts text to a value of the swagger type.
+func paramValue(swaggerType, text string) (interface{}, error) {
+	switch swaggerType {
+	case "integer":
+		return strconv.ParseInt(text, 10, 64)
+	case "number":
+		return strconv.ParseFloat(text, 64)
+	case "boolean":
+		return strconv.ParseBool(text)
+	}
+	return text, nil
+}
+
+// SetDefaultConsumes makes operations uploading files consume multipart/form-data,
+// unless @Accept says otherwise.
+func (operation *OperationObject) SetDefaultConsumes() {
+	if len(operation.Consumes) > 0 {
+		return
+	}
+	for _, parameter := range operation.Parameters {
+		if parameter.In == "formData" && (parameter.Type == "file" || (parameter.Items != nil && parameter.Items.Format == "binary")) {
+			operation.Consumes = []string{ContentTypeMultiPartFormData}
+			return
+		}
+	}
+}
+
+// ParseParamStruct adds a parameter in for every field of the struct typeName,
+// named by the form, query or header tag of the field. Fields are required like
+// those of the definition of the struct, description applies to fields without one.
+func (operation *OperationObject) ParseParamStruct(in, typeName, description string) error {
+	model := NewModel(operation.parser)
+	switch in {
+	case "query":
+		model.nameTags = []string{"query", "form"}
+	case "formData":
+		model.nameTags = []string{"form"}
+	case "header":
+		model.nameTags = []string{"header"}
+	default:
+		model.nameTags = []string{in}
+	}
+
+	if err, _ := model.ParseModel(typeName, operation.parser.CurrentPackage, map[string]bool{}); err != nil {
+		return err
+	}
+	if model.Properties == nil {
+		return fmt.Errorf("Can not expand param %s, it is not a struct.", typeName)
+	}
+
+	for _, name := range model.propertyNames {
+		property := model.Properties[name]
+		if property.Ref != "" || property.Items.Ref != "" || property.schema != nil {
+			log.Printf("Can not use field %s of %s as %s param, skipped.\n", name, typeName, in)
+			continue
+		}
+
+		swaggerParameter := &ParameterObject{
+			Name:        name,
+			In:          in,
+			Description: property.Description,
+			Required:    in == "path" || IsInStringList(model.Required, name),
+			Type:        property.Type,
+			Format:      property.Format,
+			Minimum:     property.Minimum,
+		}
+		if swaggerParameter.Description == "" {
+			swaggerParameter.Description = description
+		}
+		if property.Type == "array" {
+			swaggerParameter.Format = ""
+			swaggerParameter.Minimum = nil
+			swaggerParameter.Items = &ItemsObject{
+				Type:    property.Items.Type,
+				Format:  property.Format,
+				Minimum: property.Minimum,
+			}
+			if in == "query" || in == "formData" {
+				// Binders read repeated keys like ?tag=a&tag=b
+				swaggerParameter.CollectionFormat = "multi"
+			}
+		}
 		operation.Parameters = append(operation.Parameters, swaggerParameter)
 	}
 
@@ -656,6 +1036,8 @@ func (operation *OperationObject) ParseAcceptComment(commentLine string) error {
 			operation.Consumes = append(operation.Consumes, ContentTypeHtml)
 		case "mpfd", "multipart/form-data":
 			operation.Consumes = append(operation.Consumes, ContentTypeMultiPartFormData)
+		case "form", "application/x-www-form-urlencoded":
+			operation.Consumes = append(operation.Consumes, ContentTypeFormUrlEncoded)
 		}
 	}
 	return nil
@@ -680,90 +1062,15 @@ func (operation *OperationObject) ParseProduceComment(commentLine string) error
 	return nil
 }
 
-var typeDefTranslations = map[string]string{}
-
-var modelNamesPackageNames = map[string]string{}
-
-// refer to builtin.go
-var basicTypes = map[string]bool{
-	"bool":       true,
-	"uint":       true,
-	"uint8":      true,
-	"uint16":     true,
-	"uint32":     true,
-	"uint64":     true,
-	"int":        true,
-	"int8":       true,
-	"int16":      true,
-	"int32":      true,
-	"int64":      true,
-	"float32":    true,
-	"float64":    true,
-	"string":     true,
-	"complex64":  true,
-	"complex128": true,
-	"byte":       true,
-	"rune":       true,
-	"uintptr":    true,
-	"error":      true,
-	"Time":       true,
-	"file":       true,
-
-	"undefined": true,
-}
-
-var basicTypesSwaggerTypes = map[string]string{
-	"bool":    "boolean",
-	"uint":    "integer",
-	"uint8":   "integer",
-	"uint16":  "integer",
-	"uint32":  "integer",
-	"uint64":  "integer",
-	"int":     "integer",
-	"int8":    "integer",
-	"int16":   "integer",
-	"int32":   "integer",
-	"int64":   "integer",
-	"float32": "number",
-	"float64": "number",
-	"string":  "string",
-	"file":    "formData",
-}
-
-var basicTypesSwaggerFormats = map[string]string{
-	"bool":    "boolean",
-	"uint":    "integer",
-	"uint8":   "int64",
-	"uint16":  "int64",
-	"uint32":  "int64",
-	"uint64":  "int64",
-	"int":     "int64",
-	"int8":    "int64",
-	"int16":   "int64",
-	"int32":   "int64",
-	"int64":   "int64",
-	"float32": "float",
-	"float64": "double",
-	"string":  "string",
-}
-
-func IsBasicType(typeName string) bool {
-	_, ok := basicTypes[typeName]
-	return ok || strings.Contains(typeName, "interface")
-}
-
-func IsBasicTypeSwaggerType(typeName string) bool {
-	_, ok := basicTypesSwaggerTypes[typeName]
-	return ok || strings.Contains(typeName, "interface")
-}
-
 func (operation *OperationObject) registerType(typeName string) (string, error) {
 	registerType := ""
 
-	if translation, ok := typeDefTranslations[typeName]; ok {
+	if translation, ok := operation.parser.typeDefTranslations[typeName]; ok {
 		registerType = translation
-	} else if IsBasicType(typeName) {
+	} else if operation.parser.TypeMappings.IsBasicType(typeName) {
 		registerType = typeName
+	} else if mappedType := operation.parser.mappedType(typeName, operation.parser.CurrentPackage); mappedType != "" {
+		registerType = mappedType
 	} else {
 		model := NewModel(operation.parser)
 		knownModelNames := map[string]bool{}
@@ -773,7 +1080,7 @@ func (operation *OperationObject) registerType(typeName string) (string, error)
 		if err != nil {
 			return registerType, err
 		}
-		if translation, ok := typeDefTranslations[typeName]; ok {
+		if translation, ok := operation.parser.typeDefTranslations[typeName]; ok {
 			registerType = translation
 			// fmt.Println("## ", registerType)
 		} else {
@@ -785,32 +1092,10 @@ func (operation *OperationObject) registerType(typeName string) (string, error)
 			}
 
 			if _, ok := operation.parser.Swagger.Definitions[registerType]; !ok {
-				operation.parser.Swagger.Definitions[registerType] = &SchemaObject{
-					Type:       "object",
-					Required:   model.Required,
-					Properties: map[string]interface{}{},
-				}
-			}
-
-			for k, v := range model.Properties {
-				operation.parser.Swagger.Definitions[registerType].Properties[k] = v
+				operation.parser.Swagger.Definitions[registerType] = model.SchemaObject()
 			}
 
-			for _, m := range innerModels {
-				registerType := m.Id // local var registerType
-				if _, ok := operation.parser.Swagger.Definitions[registerType]; !ok {
-					operation.parser.Swagger.Definitions[registerType] = &SchemaObject{
-						Type:       "object",
-						Required:   m.Required,
-						Properties: map[string]interface{}{},
-					}
-				}
-
-				for k, v := range m.Properties {
-					operation.parser.Swagger.Definitions[registerType].Properties[k] = v
-				}
-				// fmt.Println("@@", m.Id)
-			}
+			operation.registerModels(innerModels)
 			// operation.Models = append(operation.Models, model)
 			// operation.Models = append(operation.Models, innerModels...)
 		}
@@ -819,11 +1104,162 @@ func (operation *OperationObject) registerType(typeName string) (string, error)
 	return registerType, nil
 }
 
+// registerModels adds the definitions of the models the registered types refer to.
+func (operation *OperationObject) registerModels(models []*Model) {
+	if operation.parser.Swagger.Definitions == nil {
+		operation.parser.Swagger.Definitions = map[string]*SchemaObject{}
+	}
+	for _, m := range models {
+		// Subtypes replace the plain definitions of their structs
+		if _, ok := operation.parser.Swagger.Definitions[m.Id]; !ok || m.parent != "" {
+			operation.parser.Swagger.Definitions[m.Id] = m.SchemaObject()
+		}
+	}
+}
+
+// anonymousSchema registers the models an anonymous struct type refers to and
+// returns the inline schema of it.
+func (operation *OperationObject) anonymousSchema(typeExpr ast.Expr) (*SchemaObject, error) {
+	model := NewModel(operation.parser)
+	schema, innerModels, err := model.typeExprSchema(typeExpr, operation.parser.CurrentPackage, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+	operation.registerModels(innerModels)
+	return schema, nil
+}
+
+// typeSchema registers typeName and returns the schema of it, typeName can
+// be nested like [][]User or map[string][]User, and compose envelopes like
+// Envelope{data=[]User,meta=PageMeta}, or be an anonymous struct like struct{Name string; Age int}.
+func (operation *OperationObject) typeSchema(typeName string) (*SchemaObject, error) {
+	if strings.HasPrefix(typeName, "struct{") {
+		typeExpr, err := goparser.ParseExpr(typeName)
+		if err != nil {
+			return nil, fmt.Errorf("Can not parse anonymous struct %s.", typeName)
+		}
+		return operation.anonymousSchema(typeExpr)
+	}
+	if i := strings.Index(typeName, "{"); i > 0 && strings.HasSuffix(typeName, "}") && !strings.HasPrefix(typeName, "[]") && !strings.HasPrefix(typeName, "map[") {
+		return operation.composedSchema(typeName[:i], typeName[i+1:len(typeName)-1])
+	}
+	if strings.HasPrefix(typeName, "[]") && operation.parser.TypeMappings[typeName] == nil {
+		items, err := operation.typeSchema(typeName[2:])
+		if err != nil {
+			return nil, err
+		}
+		return &SchemaObject{Type: "array", Items: &SchemaObjectOrArray{Schema: items}}, nil
+	}
+	if strings.HasPrefix(typeName, "map[string]") {
+		values, err := operation.typeSchema(typeName[len("map[string]"):])
+		if err != nil {
+			return nil, err
+		}
+		return &SchemaObject{Type: "object", AdditionalProperties: &BoolOrSchemaObject{Schema: values}}, nil
+	}
+
+	registeredType, err := operation.registerType(typeName)
+	if err != nil {
+		return nil, err
+	}
+	if mappings := operation.parser.mappingsOf(registeredType); mappings.IsBasicTypeSwaggerType(registeredType) {
+		return mappings.Schema(registeredType), nil
+	}
+	if _, ok := operation.parser.Swagger.Definitions[registeredType]; ok {
+		return &SchemaObject{Ref: "#/definitions/" + registeredType}, nil
+	}
+	return &SchemaObject{Type: registeredType}, nil
+}
+
+// composedSchema returns the allOf of the definition typeName and the properties
+// like data=User,meta=PageMeta overriding those of it.
+func (operation *OperationObject) composedSchema(typeName, properties string) (*SchemaObject, error) {
+	schema, err := operation.typeSchema(typeName)
+	if err != nil {
+		return nil, err
+	}
+	var definition *SchemaObject
+	if strings.HasPrefix(schema.Ref, "#/definitions/") {
+		definition = operation.parser.Swagger.Definitions[strings.TrimPrefix(schema.Ref, "#/definitions/")]
+	}
+	if definition == nil {
+		return nil, fmt.Errorf("Can not compose %s, it is not a struct.", typeName)
+	}
+	// Properties of embedded structs are in the allOf of definitions
+	definitionProperties := map[string]bool{}
+	for _, part := range append([]*SchemaObject{definition}, definition.AllOf...) {
+		if part.Ref != "" {
+			part = operation.parser.Swagger.Definitions[strings.TrimPrefix(part.Ref, "#/definitions/")]
+		}
+		if part != nil {
+			for k, _ := range part.Properties {
+				definitionProperties[k] = true
+			}
+		}
+	}
+
+	override := &SchemaObject{
+		Type:       "object",
+		Properties: map[string]*SchemaObject{},
+	}
+	for _, property := range splitOutsideBraces(properties) {
+		parts := strings.SplitN(property, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Can not parse property %s of %s, expected name=Type.", property, typeName)
+		}
+		name, propertyType := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if !definitionProperties[name] {
+			return nil, fmt.Errorf("%s has no property %s.", typeName, name)
+		}
+		if override.Properties[name], err = operation.typeSchema(propertyType); err != nil {
+			return nil, err
+		}
+	}
+
+	return &SchemaObject{AllOf: []*SchemaObject{schema, override}}, nil
+}
+
+// splitOutsideBraces splits a,b{c,d} at the commas which are not in braces.
+func splitOutsideBraces(text string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range text {
+		switch c {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, text[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, text[start:])
+}
+
 type Model struct {
 	Id         string                    `json:"id"`
 	Required   []string                  `json:"required,omitempty"`
 	Properties map[string]*ModelProperty `json:"properties"`
 	parser     *Parser
+	// Names of the properties in the order of the fields
+	propertyNames []string
+	// Tags naming the properties instead of json, like form of parameter structs
+	nameTags []string
+	// Ids of the embedded models and the models to register for them, when
+	// embedding is rendered as allOf
+	embedded       []string
+	embeddedModels []*Model
+	// Discriminator property of interfaces and the interface and value of their subtypes
+	discriminator      string
+	parent             string
+	discriminatorValue string
+	// Schema of models which are no structs, like named slices and maps
+	schema *SchemaObject
+	// Doc comment of the type
+	description string
 }
 
 type ModelProperty struct {
@@ -831,13 +1267,70 @@ type ModelProperty struct {
 	Type        string             `json:"type"`
 	Description string             `json:"description"`
 	Format      string             `json:"format"`
+	Minimum     *float64           `json:"minimum,omitempty"`
+	Nullable    bool               `json:"x-nullable,omitempty"`
+	Example     interface{}        `json:"example,omitempty"`
+	ReadOnly    bool               `json:"readOnly,omitempty"`
+	WriteOnly   bool               `json:"x-writeOnly,omitempty"`
 	Items       ModelPropertyItems `json:"items,omitempty"`
+	// Properties resolved already, like those of embedded structs by the model of the struct
+	resolved bool
+	// Type of fields with anonymous structs or maps and the inline schema of it
+	anonymous ast.Expr
+	schema    *SchemaObject
+	// Annotations of the field comments like @example and @format
+	annotations map[string]string
 }
 
 func NewModelProperty() *ModelProperty {
 	return &ModelProperty{}
 }
 
+// SchemaObject converts the property to the schema of the definition properties.
+func (p *ModelProperty) SchemaObject() *SchemaObject {
+	var schema *SchemaObject
+	if p.schema != nil {
+		copied := *p.schema
+		schema = &copied
+		schema.Example = p.Example
+	} else if p.Ref != "" {
+		schema = &SchemaObject{Ref: p.Ref}
+	} else {
+		schema = &SchemaObject{
+			Type:    p.Type,
+			Format:  p.Format,
+			Minimum: p.Minimum,
+			Example: p.Example,
+		}
+		if p.Type == "array" {
+			schema.Format = ""
+			schema.Minimum = nil
+			if p.Items.Ref != "" {
+				schema.Items = &SchemaObjectOrArray{Schema: &SchemaObject{Ref: p.Items.Ref}}
+			} else {
+				schema.Items = &SchemaObjectOrArray{Schema: &SchemaObject{Type: p.Items.Type, Format: p.Format, Minimum: p.Minimum}}
+			}
+		}
+	}
+	if schema.Ref != "" && (p.Description != "" || p.ReadOnly || p.Nullable || p.WriteOnly) {
+		// Properties beside a $ref are ignored, so the reference is wrapped in an allOf
+		schema = &SchemaObject{AllOf: []*SchemaObject{{Ref: schema.Ref}}, Example: schema.Example}
+	}
+	schema.Description = p.Description
+	schema.ReadOnly = p.ReadOnly
+	if p.Nullable || p.WriteOnly {
+		schema.Extensions = Extensions{}
+	}
+	if p.Nullable {
+		schema.Extensions["x-nullable"] = true
+	}
+	if p.WriteOnly {
+		// Swagger 2.0 has no writeOnly
+		schema.Extensions["x-writeOnly"] = true
+	}
+	return schema
+}
+
 type ModelPropertyItems struct {
 	Ref  string `json:"$ref,omitempty"`
 	Type string `json:"type,omitempty"`
@@ -859,122 +1352,349 @@ func (m *Model) ParseModel(modelName string, currentPackage string, knownModelNa
 	modelNameParts := strings.Split(modelName, ".")
 	m.Id = strings.Join(append(strings.Split(modelPackage, "/"), modelNameParts[len(modelNameParts)-1]), ".")
 
-	if _, ok := modelNamesPackageNames[modelName]; !ok {
-		modelNamesPackageNames[modelName] = m.Id
+	if _, ok := m.parser.modelNamesPackageNames[modelName]; !ok {
+		m.parser.modelNamesPackageNames[modelName] = m.Id
 	}
 
 	// fmt.Println("#", m.Id)
 
+	m.description, _ = parseDocComments(astTypeSpec.Doc)
+
+	if swaggerType, ok := m.parser.marshalerType(astTypeSpec, modelPackage); ok {
+		return m.setSwaggerType(swaggerType, astTypeSpec, modelPackage), nil
+	}
+
 	var innerModelList []*Model
-	if astTypeDef, ok := astTypeSpec.Type.(*ast.Ident); ok {
-		typeDefTranslations[m.Id] = astTypeDef.Name
-		// typeDefTranslations[astTypeSpec.Name.String()] = astTypeDef.Name
+	if astTypeDef, ok := astTypeSpec.Type.(*ast.Ident); ok && m.parser.TypeMappings.IsBasicType(astTypeDef.Name) {
+		m.parser.typeDefTranslations[m.Id] = astTypeDef.Name
+		// m.parser.typeDefTranslations[astTypeSpec.Name.String()] = astTypeDef.Name
+		m.schema, _, _ = m.typeExprSchema(astTypeDef, modelPackage, knownModelNames)
+	} else if astInterfaceType, ok := astTypeSpec.Type.(*ast.InterfaceType); ok {
+		err, subtypeModels := m.parseInterface(astTypeSpec, astInterfaceType, modelPackage, knownModelNames)
+		if err != nil {
+			return err, nil
+		}
+		innerModelList = subtypeModels
 	} else if astStructType, ok := astTypeSpec.Type.(*ast.StructType); ok {
-		m.ParseFieldList(astStructType.Fields.List, modelPackage)
-		usedTypes := make(map[string]bool)
-
-		for _, property := range m.Properties {
-			typeName := property.Type
-			if typeName == "array" {
-				if property.Items.Type != "" {
-					typeName = property.Items.Type
-				} else {
-					typeName = property.Items.Ref
-				}
+		err, structModels := m.parseStruct(astStructType, modelPackage, knownModelNames)
+		if err != nil {
+			return err, nil
+		}
+		innerModelList = structModels
+	} else {
+		err, typeModels := m.parseTypeSpec(astTypeSpec, modelName, modelPackage, knownModelNames)
+		if err != nil {
+			return err, nil
+		}
+		innerModelList = typeModels
+	}
+
+	//log.Printf("ParseModel finished %s \n", modelName)
+	return nil, append(innerModelList, m.embeddedModels...)
+}
+
+// parseStruct parses the fields of a struct into the properties of the model.
+func (m *Model) parseStruct(astStructType *ast.StructType, modelPackage string, knownModelNames map[string]bool) (error, []*Model) {
+	var innerModelList []*Model
+	var anonymousModels []*Model
+	m.ParseFieldList(astStructType.Fields.List, modelPackage)
+	usedTypes := make(map[string]bool)
+
+	for _, property := range m.Properties {
+		if property.resolved {
+			continue
+		}
+		if property.anonymous != nil {
+			schema, typeModels, err := m.typeExprSchema(property.anonymous, modelPackage, knownModelNames)
+			if err != nil {
+				return err, nil
+			}
+			property.schema = schema
+			anonymousModels = append(anonymousModels, typeModels...)
+			continue
+		}
+		typeName := property.Type
+		if typeName == "array" {
+			if property.Items.Type != "" {
+				typeName = property.Items.Type
+			} else {
+				typeName = property.Items.Ref
 			}
-			if translation, ok := typeDefTranslations[typeName]; ok {
-				typeName = translation
+		}
+		if translation, ok := m.parser.typeDefTranslations[typeName]; ok {
+			typeName = translation
+		}
+		if m.parser.mappingsOf(typeName).IsBasicType(typeName) {
+			m.setBasicType(property, typeName)
+			continue
+		}
+		if mappedType := m.parser.mappedType(typeName, modelPackage); mappedType != "" {
+			m.setBasicType(property, mappedType)
+			continue
+		}
+		if m.parser.IsImplementMarshalInterface(typeName) {
+			swaggerType := m.parser.TypesImplementingMarshalInterface[typeName]
+			if property.Type == "array" {
+				swaggerType = "array," + swaggerType
 			}
-			if IsBasicType(typeName) {
-				if IsBasicTypeSwaggerType(typeName) {
-					property.Format = basicTypesSwaggerFormats[typeName]
-					if property.Type != "array" {
-						property.Type = basicTypesSwaggerTypes[typeName]
-					} else {
-						if IsBasicType(property.Items.Type) {
-							if IsBasicTypeSwaggerType(property.Items.Type) {
-								property.Items.Type = basicTypesSwaggerTypes[property.Items.Type]
-							}
-						}
+			if err := m.setPropertySwaggerType(property, swaggerType); err != nil {
+				return err, nil
+			}
+			continue
+		}
+		if _, exists := knownModelNames[typeName]; exists {
+			// fmt.Println("@", typeName)
+			if _, ok := m.parser.modelNamesPackageNames[typeName]; ok {
+				if translation, ok := m.parser.typeDefTranslations[m.parser.modelNamesPackageNames[typeName]]; ok {
+					if m.parser.mappingsOf(translation).IsBasicType(translation) {
+						m.setBasicType(property, translation)
+						continue
 					}
 				}
-				continue
-			}
-			if m.parser.IsImplementMarshalInterface(typeName) {
-				continue
+				if property.Type != "array" {
+					property.Ref = "#/definitions/" + m.parser.modelNamesPackageNames[typeName]
+				} else {
+					property.Items.Ref = "#/definitions/" + m.parser.modelNamesPackageNames[typeName]
+				}
 			}
-			if _, exists := knownModelNames[typeName]; exists {
-				// fmt.Println("@", typeName)
-				if _, ok := modelNamesPackageNames[typeName]; ok {
-					if translation, ok := typeDefTranslations[modelNamesPackageNames[typeName]]; ok {
-						if IsBasicType(translation) {
-							if IsBasicTypeSwaggerType(translation) {
-								// fmt.Println(modelNamesPackageNames[typeName], translation)
-								property.Type = basicTypesSwaggerTypes[translation]
+			continue
+		}
+
+		usedTypes[typeName] = true
+	}
+
+	//log.Printf("Before parse inner model list: %#v\n (%s)", usedTypes, modelName)
+	innerModelList = make([]*Model, 0, len(usedTypes))
+
+	for typeName, _ := range usedTypes {
+		typeModel := NewModel(m.parser)
+		if err, typeInnerModels := typeModel.ParseModel(typeName, modelPackage, knownModelNames); err != nil {
+			//log.Printf("Parse Inner Model error %#v \n", err)
+			return err, nil
+		} else {
+			for _, property := range m.Properties {
+				if property.Type == "array" {
+					if property.Items.Ref == typeName {
+						property.Items.Ref = "#/definitions/" + typeModel.Id
+					}
+				} else {
+					if property.Type == typeName {
+						if translation, ok := m.parser.typeDefTranslations[m.parser.modelNamesPackageNames[typeName]]; ok {
+							if m.parser.mappingsOf(translation).IsBasicType(translation) {
+								m.setBasicType(property, translation)
+								continue
 							}
-							continue
 						}
-					}
-					if property.Type != "array" {
-						property.Ref = "#/definitions/" + modelNamesPackageNames[typeName]
+						property.Ref = "#/definitions/" + typeModel.Id
+						// property.Type = typeModel.Id
 					} else {
-						property.Items.Ref = "#/definitions/" + modelNamesPackageNames[typeName]
+						// fmt.Println(property.Type, "<>", typeName)
 					}
 				}
-				continue
 			}
+			//log.Printf("Inner model %v parsed, parsing %s \n", typeName, modelName)
+			if typeModel != nil {
+				innerModelList = append(innerModelList, typeModel)
+			}
+			if typeInnerModels != nil && len(typeInnerModels) > 0 {
+				innerModelList = append(innerModelList, typeInnerModels...)
+			}
+			//log.Printf("innerModelList: %#v\n, typeInnerModels: %#v, usedTypes: %#v \n", innerModelList, typeInnerModels, usedTypes)
+		}
+	}
+	//log.Printf("After parse inner model list: %#v\n (%s)", usedTypes, modelName)
+	// log.Fatalf("Inner model list: %#v\n", innerModelList)
 
-			usedTypes[typeName] = true
+	for name, property := range m.Properties {
+		if err := property.applyAnnotations(); err != nil {
+			log.Printf("Can not use the comments of field %s: %v\n", name, err)
 		}
+	}
 
-		//log.Printf("Before parse inner model list: %#v\n (%s)", usedTypes, modelName)
-		innerModelList = make([]*Model, 0, len(usedTypes))
+	return nil, append(innerModelList, anonymousModels...)
+}
 
-		for typeName, _ := range usedTypes {
-			typeModel := NewModel(m.parser)
-			if err, typeInnerModels := typeModel.ParseModel(typeName, modelPackage, knownModelNames); err != nil {
-				//log.Printf("Parse Inner Model error %#v \n", err)
-				return err, nil
-			} else {
-				for _, property := range m.Properties {
-					if property.Type == "array" {
-						if property.Items.Ref == typeName {
-							property.Items.Ref = "#/definitions/" + typeModel.Id
-						}
-					} else {
-						if property.Type == typeName {
-							if translation, ok := typeDefTranslations[modelNamesPackageNames[typeName]]; ok {
-								if IsBasicType(translation) {
-									if IsBasicTypeSwaggerType(translation) {
-										property.Type = basicTypesSwaggerTypes[translation]
-									}
-									continue
-								}
-							}
-							property.Ref = "#/definitions/" + typeModel.Id
-							// property.Type = typeModel.Id
-						} else {
-							// fmt.Println(property.Type, "<>", typeName)
-						}
-					}
-				}
-				//log.Printf("Inner model %v parsed, parsing %s \n", typeName, modelName)
-				if typeModel != nil {
-					innerModelList = append(innerModelList, typeModel)
-				}
-				if typeInnerModels != nil && len(typeInnerModels) > 0 {
-					innerModelList = append(innerModelList, typeInnerModels...)
-				}
-				//log.Printf("innerModelList: %#v\n, typeInnerModels: %#v, usedTypes: %#v \n", innerModelList, typeInnerModels, usedTypes)
+// setBasicType sets the swagger type of a property or its items of the basic type typeName.
+func (m *Model) setBasicType(property *ModelProperty, typeName string) {
+	mappings := m.parser.mappingsOf(typeName)
+	if !mappings.IsBasicTypeSwaggerType(typeName) {
+		// Types like error have no swagger type, any value is valid
+		typeName = "interface"
+	}
+	property.Format = mappings.Format(typeName)
+	property.Minimum = mappings.Minimum(typeName)
+	if property.Type != "array" {
+		property.Type = mappings.Type(typeName)
+		property.Nullable = property.Nullable || mappings.Nullable(typeName)
+	} else {
+		property.Items.Type = mappings.Type(typeName)
+		property.Items.Ref = ""
+	}
+}
+
+// parseTypeSpec parses the type specs which are no structs, interfaces or basic types:
+// named slices and maps, named types of other models, pointers and aliases.
+func (m *Model) parseTypeSpec(astTypeSpec *ast.TypeSpec, modelName string, modelPackage string, knownModelNames map[string]bool) (error, []*Model) {
+	typeExpr := astTypeSpec.Type
+	for {
+		if astStarExpr, ok := typeExpr.(*ast.StarExpr); ok {
+			typeExpr = astStarExpr.X
+		} else {
+			break
+		}
+	}
+
+	switch typeExpr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		typeName := NewModelProperty().GetTypeAsString(typeExpr)
+		if mappedType := m.parser.mappedType(typeName, modelPackage); mappedType != "" {
+			typeName = mappedType
+		}
+		if m.parser.TypeMappings.IsBasicType(typeName) {
+			m.parser.typeDefTranslations[m.Id] = typeName
+			m.schema, _, _ = m.typeExprSchema(typeExpr, modelPackage, knownModelNames)
+			return nil, nil
+		}
+
+		target := NewModel(m.parser)
+		err, innerModels := target.ParseModel(typeName, modelPackage, knownModelNames)
+		if err != nil {
+			return err, nil
+		}
+		if astTypeSpec.Assign.IsValid() {
+			// Aliases are the same type, so they share the definition
+			*m = *target
+			m.parser.modelNamesPackageNames[modelName] = target.Id
+			return nil, innerModels
+		}
+		// Named types have the fields of the underlying type
+		id, description := m.Id, m.description
+		*m = *target
+		m.Id = id
+		if description != "" {
+			m.description = description
+		}
+		if translation, ok := m.parser.typeDefTranslations[target.Id]; ok {
+			m.parser.typeDefTranslations[m.Id] = translation
+		}
+		return nil, innerModels
+	default:
+		schema, innerModels, err := m.typeExprSchema(typeExpr, modelPackage, knownModelNames)
+		if err != nil {
+			return err, nil
+		}
+		m.schema = schema
+		return nil, innerModels
+	}
+}
+
+// typeExprSchema returns the schema of a type expression and the models it refers to.
+func (m *Model) typeExprSchema(typeExpr ast.Expr, modelPackage string, knownModelNames map[string]bool) (*SchemaObject, []*Model, error) {
+	switch astType := typeExpr.(type) {
+	case *ast.StarExpr:
+		return m.typeExprSchema(astType.X, modelPackage, knownModelNames)
+	case *ast.ArrayType:
+		if typeName := NewModelProperty().GetTypeAsString(astType); m.parser.TypeMappings[typeName] != nil {
+			// Slices with a mapping of their own like []byte
+			return m.parser.TypeMappings.Schema(typeName), nil, nil
+		}
+		items, innerModels, err := m.typeExprSchema(astType.Elt, modelPackage, knownModelNames)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &SchemaObject{Type: "array", Items: &SchemaObjectOrArray{Schema: items}}, innerModels, nil
+	case *ast.MapType:
+		values, innerModels, err := m.typeExprSchema(astType.Value, modelPackage, knownModelNames)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &SchemaObject{Type: "object", AdditionalProperties: &BoolOrSchemaObject{Schema: values}}, innerModels, nil
+	case *ast.InterfaceType:
+		return &SchemaObject{}, nil, nil
+	case *ast.StructType:
+		// Anonymous structs are inlined
+		inner := NewModel(m.parser)
+		inner.nameTags = m.nameTags
+		err, innerModels := inner.parseStruct(astType, modelPackage, knownModelNames)
+		if err != nil {
+			return nil, nil, err
+		}
+		if inner.Properties == nil {
+			inner.Properties = map[string]*ModelProperty{}
+		}
+		return inner.SchemaObject(), append(innerModels, inner.embeddedModels...), nil
+	case *ast.Ident, *ast.SelectorExpr:
+		typeName := NewModelProperty().GetTypeAsString(typeExpr)
+		if mappedType := m.parser.mappedType(typeName, modelPackage); mappedType != "" {
+			typeName = mappedType
+		}
+		if m.parser.TypeMappings.IsBasicType(typeName) {
+			return m.parser.TypeMappings.Schema(typeName), nil, nil
+		}
+		if knownModelNames[typeName] {
+			if id, ok := m.parser.modelNamesPackageNames[typeName]; ok {
+				return m.translatedSchema(id, nil)
 			}
 		}
-		//log.Printf("After parse inner model list: %#v\n (%s)", usedTypes, modelName)
-		// log.Fatalf("Inner model list: %#v\n", innerModelList)
 
+		typeModel := NewModel(m.parser)
+		err, innerModels := typeModel.ParseModel(typeName, modelPackage, knownModelNames)
+		if err != nil {
+			return nil, nil, err
+		}
+		return m.translatedSchema(typeModel.Id, append([]*Model{typeModel}, innerModels...))
 	}
 
-	//log.Printf("ParseModel finished %s \n", modelName)
-	return nil, innerModelList
+	log.Printf("Can not document type %s of package %s, it is no JSON value.\n", NewModelProperty().GetTypeAsString(typeExpr), modelPackage)
+	return &SchemaObject{}, nil, nil
+}
+
+// translatedSchema returns the basic schema of basic typedefs and a reference otherwise.
+func (m *Model) translatedSchema(id string, models []*Model) (*SchemaObject, []*Model, error) {
+	if translation, ok := m.parser.typeDefTranslations[id]; ok && m.parser.mappingsOf(translation).IsBasicTypeSwaggerType(translation) {
+		return m.parser.mappingsOf(translation).Schema(translation), nil, nil
+	}
+	return &SchemaObject{Ref: "#/definitions/" + id}, models, nil
+}
+
+// SchemaObject converts the model to a definition, the allOf of the embedded
+// models and its own properties when embedding is rendered as allOf.
+func (m *Model) SchemaObject() *SchemaObject {
+	if m.schema != nil {
+		schema := *m.schema
+		if m.description != "" {
+			schema.Description = m.description
+		}
+		return &schema
+	}
+	schema := &SchemaObject{
+		Type:       "object",
+		Required:   m.Required,
+		Properties: map[string]*SchemaObject{},
+	}
+	for k, v := range m.Properties {
+		schema.Properties[k] = v.SchemaObject()
+	}
+	schema.Discriminator = m.discriminator
+
+	var allOf []*SchemaObject
+	if m.parent != "" {
+		allOf = append(allOf, &SchemaObject{Ref: "#/definitions/" + m.parent})
+	}
+	for _, id := range m.embedded {
+		allOf = append(allOf, &SchemaObject{Ref: "#/definitions/" + id})
+	}
+	if len(allOf) == 0 {
+		schema.Description = m.description
+		return schema
+	}
+
+	composed := &SchemaObject{AllOf: append(allOf, schema), Description: m.description}
+	if m.discriminatorValue != "" {
+		// Swagger 2.0 takes the definition names as discriminator values
+		composed.Extensions = Extensions{"x-discriminator-value": m.discriminatorValue}
+	}
+	return composed
 }
 
 func (m *Model) ParseFieldList(fieldList []*ast.Field, modelPackage string) {
@@ -1006,9 +1726,15 @@ func (m *Model) ParseModelProperty(field *ast.Field, modelPackage string) {
 	reInternalRepresentation := regexp.MustCompile("&\\{(\\w*) (\\w*)\\}")
 	typeAsString = string(reInternalRepresentation.ReplaceAll([]byte(typeAsString), []byte("$1.$2")))
 
-	if strings.HasPrefix(typeAsString, "[]") {
+	if hasAnonymousStruct(field.Type) || hasMapType(field.Type) {
+		property.Type = "object"
+		property.anonymous = field.Type
+	} else if m.parser.TypeMappings[typeAsString] != nil {
+		// Slices with a mapping of their own like []byte
+		property.Type = typeAsString
+	} else if strings.HasPrefix(typeAsString, "[]") {
 		property.Type = "array"
-		property.SetItemType(typeAsString[2:])
+		property.SetItemType(typeAsString[2:], m.parser.TypeMappings)
 		// if is Unsupported item type of list, ignore this property
 		if property.Items.Type == "undefined" {
 			property = nil
@@ -1016,14 +1742,12 @@ func (m *Model) ParseModelProperty(field *ast.Field, modelPackage string) {
 		}
 	} else if strings.HasPrefix(typeAsString, "*[]") {
 		property.Type = "array"
-		property.SetItemType(typeAsString[3:])
+		property.SetItemType(typeAsString[3:], m.parser.TypeMappings)
 		// if is Unsupported item type of list, ignore this property
 		if property.Items.Type == "undefined" {
 			property = nil
 			return
 		}
-	} else if typeAsString == "time.Time" {
-		property.Type = "Time"
 	} else {
 		property.Type = typeAsString
 	}
@@ -1043,26 +1767,46 @@ func (m *Model) ParseModelProperty(field *ast.Field, modelPackage string) {
 				name = astIdent.Name
 			}
 		} else {
-			log.Fatalf("Something goes wrong: %#v", field.Type)
+			m.parser.fatalf("Something goes wrong: %#v", field.Type)
 		}
 		innerModel = NewModel(m.parser)
+		innerModel.nameTags = m.nameTags
 		//log.Printf("Try to parse embeded type %s \n", name)
 		//log.Fatalf("DEBUG: field: %#v\n, selector.X: %#v\n selector.Sel: %#v\n", field, astSelectorExpr.X, astSelectorExpr.Sel)
 		knownModelNames := map[string]bool{}
-		innerModel.ParseModel(name, modelPackage, knownModelNames)
+		err, innerModels := innerModel.ParseModel(name, modelPackage, knownModelNames)
+		if err != nil {
+			log.Printf("Can not parse embedded type %s of %s: %v\n", name, m.Id, err)
+			return
+		}
+		if m.parser.EmbeddedAllOf && m.nameTags == nil && innerModel.Properties != nil {
+			m.embedded = append(m.embedded, innerModel.Id)
+			m.embeddedModels = append(append(m.embeddedModels, innerModel), innerModels...)
+			return
+		}
 
-		for innerFieldName, innerField := range innerModel.Properties {
-			m.Properties[innerFieldName] = innerField
+		for _, innerFieldName := range innerModel.propertyNames {
+			innerModel.Properties[innerFieldName].resolved = true
+			m.setProperty(innerFieldName, innerModel.Properties[innerFieldName])
 		}
+		// Fields of embedded structs are required like the fields of the struct
+		m.Required = append(m.Required, innerModel.Required...)
+		// Definitions the embedded properties refer to
+		m.embeddedModels = append(m.embeddedModels, innerModels...)
 
 		//log.Fatalf("Here %#v\n", field.Type)
 		return
 	} else {
 		name = field.Names[0].Name
 	}
+	if m.nameTags != nil && !ast.IsExported(name) {
+		// Binders ignore unexported fields
+		return
+	}
 
 	//log.Printf("ParseModelProperty: %s, CurrentPackage %s, type: %s \n", name, modelPackage, property.Type)
 	//Analyse struct fields annotations
+	var omitempty bool
 	if field.Tag != nil {
 		structTag := reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
 		var tagText string
@@ -1072,12 +1816,19 @@ func (m *Model) ParseModelProperty(field *ast.Field, modelPackage string) {
 		if tag := structTag.Get("json"); tag != "" {
 			tagText = tag
 		}
+		for _, nameTag := range m.nameTags {
+			if tag := structTag.Get(nameTag); tag != "" {
+				tagText = tag
+				break
+			}
+		}
 
 		tagValues := strings.Split(tagText, ",")
 		var isRequired = false
 
 		for _, v := range tagValues {
-			if v != "" && v != "required" && v != "omitempty" {
+			// Options like default=1 of gin form tags are no names
+			if v != "" && v != "required" && v != "omitempty" && !(m.nameTags != nil && strings.Contains(v, "=")) {
 				name = v
 			}
 			if v == "required" {
@@ -1091,17 +1842,143 @@ func (m *Model) ParseModelProperty(field *ast.Field, modelPackage string) {
 		if required := structTag.Get("required"); required != "" || isRequired {
 			m.Required = append(m.Required, name)
 		}
+		omitempty = IsInStringList(tagValues, "omitempty")
+		property.ReadOnly = structTag.Get("readonly") == "true"
+		property.WriteOnly = structTag.Get("writeonly") == "true"
+		if swaggerType := structTag.Get("swaggertype"); swaggerType != "" {
+			if err := m.setPropertySwaggerType(property, swaggerType); err != nil {
+				log.Printf("Can not use swaggertype of field %s: %v\n", name, err)
+			}
+		}
 		if desc := structTag.Get("description"); desc != "" {
 			property.Description = desc
 		}
 	}
+	description, annotations := parseDocComments(field.Doc)
+	lineDescription, lineAnnotations := parseDocComments(field.Comment)
+	if description == "" {
+		description = lineDescription
+	}
+	for k, v := range lineAnnotations {
+		if _, ok := annotations[k]; !ok {
+			annotations[k] = v
+		}
+	}
+	if property.Description == "" {
+		property.Description = description
+	}
+	property.annotations = annotations
+	if _, ok := annotations["@readonly"]; ok {
+		property.ReadOnly = true
+	}
+	if _, ok := annotations["@writeonly"]; ok {
+		property.WriteOnly = true
+	}
+
+	if m.parser.OmitemptyPolicy && m.nameTags == nil {
+		if _, ok := field.Type.(*ast.StarExpr); ok {
+			property.Nullable = true
+		} else if !omitempty && !property.ReadOnly && ast.IsExported(field.Names[0].Name) && !IsInStringList(m.Required, name) {
+			// encoding/json always writes these fields
+			m.Required = append(m.Required, name)
+		}
+	}
+	m.setProperty(name, property)
+}
+
+// parseDocComments returns the text of doc without the lines of annotations, and
+// the annotations like @example 42 by their lower case names.
+func parseDocComments(doc *ast.CommentGroup) (string, map[string]string) {
+	annotations := map[string]string{}
+	if doc == nil {
+		return "", annotations
+	}
+	var lines []string
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "@") {
+			fields := strings.SplitN(line, " ", 2)
+			annotations[strings.ToLower(fields[0])] = ""
+			if len(fields) > 1 {
+				annotations[strings.ToLower(fields[0])] = strings.TrimSpace(fields[1])
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), annotations
+}
+
+// applyAnnotations sets the format and example of @format and @example comments,
+// they apply to the items of arrays.
+func (p *ModelProperty) applyAnnotations() error {
+	if format, ok := p.annotations["@format"]; ok {
+		p.Format = format
+	}
+	example, ok := p.annotations["@example"]
+	if !ok || p.Ref != "" {
+		return nil
+	}
+	if p.Type != "array" {
+		value, err := paramValue(p.Type, example)
+		if err != nil {
+			return err
+		}
+		p.Example = value
+		return nil
+	}
+	values := []interface{}{}
+	for _, v := range strings.Split(example, ",") {
+		value, err := paramValue(p.Items.Type, strings.TrimSpace(v))
+		if err != nil {
+			return err
+		}
+		values = append(values, value)
+	}
+	p.Example = values
+	return nil
+}
+
+// hasAnonymousStruct reports whether typeExpr is an anonymous struct, or a pointer,
+// slice or map of them.
+func hasAnonymousStruct(typeExpr ast.Expr) bool {
+	switch astType := typeExpr.(type) {
+	case *ast.StructType:
+		return true
+	case *ast.StarExpr:
+		return hasAnonymousStruct(astType.X)
+	case *ast.ArrayType:
+		return hasAnonymousStruct(astType.Elt)
+	case *ast.MapType:
+		return hasAnonymousStruct(astType.Value)
+	}
+	return false
+}
+
+// hasMapType reports whether typeExpr is a map, or a pointer or slice of them.
+func hasMapType(typeExpr ast.Expr) bool {
+	switch astType := typeExpr.(type) {
+	case *ast.MapType:
+		return true
+	case *ast.StarExpr:
+		return hasMapType(astType.X)
+	case *ast.ArrayType:
+		return hasMapType(astType.Elt)
+	}
+	return false
+}
+
+func (m *Model) setProperty(name string, property *ModelProperty) {
+	if _, ok := m.Properties[name]; !ok {
+		m.propertyNames = append(m.propertyNames, name)
+	}
 	m.Properties[name] = property
 }
 
-func (p *ModelProperty) SetItemType(itemType string) {
+func (p *ModelProperty) SetItemType(itemType string, mappings TypeMappings) {
 	p.Items = ModelPropertyItems{}
-	if IsBasicType(itemType) {
-		if IsBasicTypeSwaggerType(itemType) {
+	if mappings.IsBasicType(itemType) {
+		if mappings.IsBasicTypeSwaggerType(itemType) {
 			p.Items.Type = itemType
 		} else {
 			p.Items.Type = "undefined"
@@ -1120,7 +1997,7 @@ func (p *ModelProperty) GetTypeAsString(fieldType interface{}) string {
 		realType = fmt.Sprintf("[]%v", p.GetTypeAsString(astArrayType.Elt))
 	} else if astMapType, ok := fieldType.(*ast.MapType); ok {
 		//		log.Printf("arrayType: %#v\n", astArrayType)
-		realType = fmt.Sprintf("[]%v", p.GetTypeAsString(astMapType.Value))
+		realType = fmt.Sprintf("map[%v]%v", p.GetTypeAsString(astMapType.Key), p.GetTypeAsString(astMapType.Value))
 	} else if _, ok := fieldType.(*ast.InterfaceType); ok {
 		realType = "interface"
 	} else {
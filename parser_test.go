@@ -0,0 +1,246 @@
+package mswagger
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func TestModelPropertySchemaObject(t *testing.T) {
+	tests := []struct {
+		name     string
+		property *ModelProperty
+		expected string
+	}{
+		{
+			name:     "type",
+			property: &ModelProperty{Type: "integer", Format: "int64", Description: "Id of the user"},
+			expected: `{"type": "integer", "format": "int64", "description": "Id of the user"}`,
+		},
+		{
+			name:     "array",
+			property: &ModelProperty{Type: "array", Items: ModelPropertyItems{Ref: "#/definitions/User"}},
+			expected: `{"type": "array", "items": {"$ref": "#/definitions/User"}}`,
+		},
+		{
+			name:     "ref",
+			property: &ModelProperty{Ref: "#/definitions/User"},
+			expected: `{"$ref": "#/definitions/User"}`,
+		},
+		{
+			name:     "ref with description",
+			property: &ModelProperty{Ref: "#/definitions/User", Description: "Owner of the item"},
+			expected: `{"allOf": [{"$ref": "#/definitions/User"}], "description": "Owner of the item"}`,
+		},
+		{
+			name:     "nullable read only ref",
+			property: &ModelProperty{Ref: "#/definitions/User", Nullable: true, ReadOnly: true},
+			expected: `{"allOf": [{"$ref": "#/definitions/User"}], "readOnly": true, "x-nullable": true}`,
+		},
+		{
+			name:     "write only ref",
+			property: &ModelProperty{Ref: "#/definitions/Password", WriteOnly: true},
+			expected: `{"allOf": [{"$ref": "#/definitions/Password"}], "x-writeOnly": true}`,
+		},
+	}
+
+	for _, test := range tests {
+		var expected interface{}
+		if err := json.Unmarshal([]byte(test.expected), &expected); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		schema := test.property.SchemaObject()
+		if !JsonEqual(schema, expected) {
+			actual, _ := json.Marshal(schema)
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestParseParamStruct(t *testing.T) {
+	tests := []struct {
+		name     string
+		comment  string
+		expected string
+	}{
+		{
+			name:    "query",
+			comment: `@Param  _  query  ListFilter  true  "Filters of the list."`,
+			expected: `[
+			  {"name": "page", "in": "query", "description": "Page of the list.", "type": "integer", "format": "int64"},
+			  {"name": "sort", "in": "query", "description": "Filters of the list.", "required": true, "type": "string"},
+			  {"name": "tags", "in": "query", "description": "Filters of the list.", "type": "array", "items": {"type": "string"}, "collectionFormat": "multi"}]`,
+		},
+		{
+			name:    "header",
+			comment: `@Param  _  header  Tenant  false  "Tenant of the request."`,
+			expected: `[
+			  {"name": "X-Tenant-Id", "in": "header", "description": "Tenant of the request.", "required": true, "type": "string"}]`,
+		},
+	}
+
+	packageName := "testdata/params"
+	packagePath, err := filepath.Abs(packageName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		parser := NewParser()
+		parser.PackagePathCache[packageName] = packagePath
+		parser.CurrentPackage = packageName
+		parser.ParseTypeDefinitions(packageName)
+		operation := NewOperationObject(parser, packageName)
+		if err := operation.ParseComment(test.comment); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+
+		var expected interface{}
+		if err := json.Unmarshal([]byte(test.expected), &expected); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !JsonEqual(operation.Parameters, expected) {
+			actual, _ := json.Marshal(operation.Parameters)
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestParseParamComment(t *testing.T) {
+	tests := []struct {
+		name     string
+		comment  string
+		expected string
+		err      string
+	}{
+		{
+			name:     "enums and default",
+			comment:  `sort  query  string  false  "Order of the list."  enums(name, age)  default(name)`,
+			expected: `{"name": "sort", "in": "query", "description": "Order of the list.", "type": "string", "enum": ["name", "age"], "default": "name"}`,
+		},
+		{
+			name:    "limits and example",
+			comment: `limit  query  int  false  "Size of the page."  minimum(1)  maximum(100)  example(20)`,
+			expected: `{"name": "limit", "in": "query", "description": "Size of the page.", "type": "integer", "format": "int64",
+			  "minimum": 1, "maximum": 100, "x-example": 20}`,
+		},
+		{
+			name:    "pattern and lengths",
+			comment: `code  path  string  true  "Code of the country."  pattern(^([A-Z]{2}|[A-Z]{3})$)  minlength(2)  maxlength(3)  format(iso3166)`,
+			expected: `{"name": "code", "in": "path", "description": "Code of the country.", "required": true, "type": "string", "format": "iso3166",
+			  "pattern": "^([A-Z]{2}|[A-Z]{3})$", "minLength": 2, "maxLength": 3}`,
+		},
+		{
+			name:    "array",
+			comment: `ids  query  []int  false  "Ids of the users."  collectionFormat(csv)  enums(1,2,3)  default(1,2)`,
+			expected: `{"name": "ids", "in": "query", "description": "Ids of the users.", "type": "array",
+			  "items": {"type": "integer", "format": "int64", "enum": [1, 2, 3]}, "collectionFormat": "csv", "default": [1, 2]}`,
+		},
+		{
+			name:    "multi outside of query",
+			comment: `ids  header  []string  false  "Ids of the users."  collectionFormat(multi)`,
+			err:     "Param ids is in header, collectionFormat(multi) is only allowed in query and formData.",
+		},
+		{
+			name:    "unknown attribute",
+			comment: `page  query  int  false  "Page of the list."  step(2)`,
+			err:     "Unknown attribute step(2) of param page.",
+		},
+		{
+			name:    "invalid enum",
+			comment: `page  query  int  false  "Page of the list."  enums(1,two)`,
+			err:     `Can not parse enums(1,two) of param page: strconv.ParseInt: parsing "two": invalid syntax`,
+		},
+		{
+			name:    "attribute of body",
+			comment: `user  body  string  true  "The user."  minlength(1)`,
+			err:     "Param user is in body, the attribute minlength belongs in the schema.",
+		},
+	}
+
+	for _, test := range tests {
+		operation := NewOperationObject(NewParser(), "handlers")
+		err := operation.ParseParamComment(test.comment)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("%s: expected error %q, got %v", test.name, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+
+		var expected interface{}
+		if err := json.Unmarshal([]byte(test.expected), &expected); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if len(operation.Parameters) != 1 || !JsonEqual(operation.Parameters[0], expected) {
+			actual, _ := json.Marshal(operation.Parameters)
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestParseDiscriminator(t *testing.T) {
+	tests := []struct {
+		name     string
+		comment  string
+		expected string
+	}{
+		{
+			name:    "subtypes",
+			comment: "@Success 200 {object} Event",
+			expected: `{
+			  "testdata.discriminator.Event": {"description": "Event is sent to the subscribers.", "type": "object",
+			    "discriminator": "kind", "required": ["kind"], "properties": {"kind": {"type": "string"}}},
+			  "testdata.discriminator.CreatedEvent": {"x-discriminator-value": "created", "allOf": [
+			    {"$ref": "#/definitions/testdata.discriminator.Event"},
+			    {"type": "object", "properties": {"id": {"type": "string"}}}]},
+			  "testdata.discriminator.DeletedEvent": {"x-discriminator-value": "deleted", "allOf": [
+			    {"$ref": "#/definitions/testdata.discriminator.Event"},
+			    {"type": "object", "properties": {"id": {"type": "string"}, "reason": {"type": "string"}}}]}}`,
+		},
+		{
+			name:    "implementations",
+			comment: "@Success 200 {object} Shape",
+			expected: `{
+			  "testdata.discriminator.Shape": {"description": "Shape is drawn on the canvas.", "type": "object",
+			    "discriminator": "type", "required": ["type"], "properties": {"type": {"type": "string"}}},
+			  "testdata.discriminator.Circle": {"x-discriminator-value": "Circle", "allOf": [
+			    {"$ref": "#/definitions/testdata.discriminator.Shape"},
+			    {"type": "object", "properties": {"radius": {"type": "number", "format": "double"}}}]},
+			  "testdata.discriminator.Square": {"x-discriminator-value": "Square", "allOf": [
+			    {"$ref": "#/definitions/testdata.discriminator.Shape"},
+			    {"type": "object", "properties": {"side": {"type": "number", "format": "double"}}}]}}`,
+		},
+	}
+
+	packageName := "testdata/discriminator"
+	packagePath, err := filepath.Abs(packageName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		parser := NewParser()
+		parser.PackagePathCache[packageName] = packagePath
+		parser.ParseTypeDefinitions(packageName)
+		operation := NewOperationObject(parser, packageName)
+		if err := operation.ParseComment(test.comment); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+
+		var expected interface{}
+		if err := json.Unmarshal([]byte(test.expected), &expected); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !JsonEqual(parser.Swagger.Definitions, expected) {
+			actual, _ := json.Marshal(parser.Swagger.Definitions)
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, actual)
+		}
+	}
+}
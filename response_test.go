@@ -0,0 +1,102 @@
+package mswagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files of testdata")
+
+func TestParseResponseComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		comment string
+	}{
+		{name: "object_200", comment: "@Success 200 {object} User"},
+		{name: "object_201", comment: "@Success 201 {object} User \"Created\""},
+		{name: "object_404", comment: "@Failure 404 {object} Error \"Not found\""},
+		{name: "object_default", comment: "@Failure default {object} Error"},
+		{name: "array_200", comment: "@Success 200 {array} User"},
+		{name: "array_201", comment: "@Success 201 {array} User"},
+		{name: "array_422", comment: "@Failure 422 {array} Error"},
+		{name: "array_default", comment: "@Failure default {array} Error"},
+		{name: "array_string_200", comment: "@Success 200 {array} string"},
+		{name: "map_200", comment: "@Success 200 {map} User"},
+		{name: "map_201", comment: "@Success 201 {map} int"},
+		{name: "map_400", comment: "@Failure 400 {map} Error"},
+		{name: "map_default", comment: "@Failure default {map} []Error"},
+		{name: "nested_array_200", comment: "@Success 200 {object} [][]User"},
+		{name: "nested_array_201", comment: "@Success 201 {array} []User"},
+		{name: "nested_array_409", comment: "@Failure 409 {object} [][]Error"},
+		{name: "nested_array_default", comment: "@Failure default {object} map[string][][]User"},
+		{name: "no_body_204", comment: "@Success 204"},
+		{name: "no_body_299", comment: "@Success 299"},
+	}
+
+	packageName := "testdata/responses"
+	packagePath, err := filepath.Abs(packageName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		parser := NewParser()
+		parser.PackagePathCache[packageName] = packagePath
+		parser.ParseTypeDefinitions(packageName)
+		operation := NewOperationObject(parser, packageName)
+		if err := operation.ParseComment(test.comment); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+
+		actual, err := json.MarshalIndent(map[string]interface{}{
+			"responses":   operation.Responses,
+			"definitions": parser.Swagger.Definitions,
+		}, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual = append(actual, '\n')
+
+		golden := filepath.Join("testdata", "responses", test.name+".golden.json")
+		if *update {
+			if err := ioutil.WriteFile(golden, actual, 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		expected, err := ioutil.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(actual, expected) {
+			t.Errorf("%s: %s does not match\n%s", test.name, golden, actual)
+		}
+	}
+}
+
+func TestResponseObjectMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		response *ResponseObject
+		expected string
+	}{
+		{name: "description", response: &ResponseObject{Description: "OK"}, expected: `{"description":"OK"}`},
+		{name: "empty description", response: &ResponseObject{}, expected: `{"description":""}`},
+		{name: "reference", response: &ResponseObject{Ref: "#/responses/NotFound"}, expected: `{"$ref":"#/responses/NotFound"}`},
+	}
+
+	for _, test := range tests {
+		actual, err := json.Marshal(test.response)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if string(actual) != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,777 @@
+package mswagger
+
+import (
+	"go/ast"
+	"go/token"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// Route is a route registration found in the source code by ParseRoutes.
+type Route struct {
+	Method string
+	Path   string
+	// HandlerPackage is the import path of the handler, empty if it is unknown.
+	HandlerPackage string
+	// HandlerRecv is the receiver type of a method handler without "*",
+	// "*" if the receiver type is unknown and empty for functions. Unknown
+	// receiver types are resolved by ParseApi if only one controller fits.
+	HandlerRecv string
+	HandlerName string
+}
+
+// handlerAdapters are the functions taking the handler as their last argument,
+// keyed by import path and name.
+var handlerAdapters = map[string]bool{
+	"net/http.HandlerFunc":                    true,
+	"net/http.StripPrefix":                    true,
+	"github.com/gin-gonic/gin.WrapF":          true,
+	"github.com/gin-gonic/gin.WrapH":          true,
+	"github.com/labstack/echo.WrapHandler":    true,
+	"github.com/labstack/echo/v4.WrapHandler": true,
+}
+
+// routeScope holds what is known about the variables of a function while its
+// route registrations are collected.
+type routeScope struct {
+	parser      *Parser
+	packageName string
+	imports     map[string]string
+	// path prefixes of router variables
+	prefixes map[string]string
+	// types of variables as [import path, type name]
+	types map[string][2]string
+	// closures assigned to variables
+	closures map[string]*ast.FuncLit
+}
+
+// routeChain is the result of a chain of router calls like
+// r.PathPrefix("/api").Subrouter() or r.HandleFunc("/user", h).Methods("POST").
+type routeChain struct {
+	prefix   string
+	path     string
+	methods  []string
+	handlers []ast.Expr
+	isRouter bool
+}
+
+// ParseRoutes collects the route registrations of net/http, gorilla/mux, gin, echo and chi in packageName.
+func (parser *Parser) ParseRoutes(packageName string) {
+	pkgRealPath := parser.GetRealPackagePath(packageName)
+	if pkgRealPath == "" {
+		return
+	}
+
+	astPackages := parser.GetPackageAst(pkgRealPath)
+	for _, astPackage := range astPackages {
+		for _, astFile := range astPackage.Files {
+			imports := fileImports(astFile)
+			for _, astDeclaration := range astFile.Decls {
+				funcDeclaration, ok := astDeclaration.(*ast.FuncDecl)
+				if !ok || funcDeclaration.Body == nil {
+					continue
+				}
+				scope := &routeScope{
+					parser:      parser,
+					packageName: packageName,
+					imports:     imports,
+					prefixes:    map[string]string{},
+					types:       map[string][2]string{},
+					closures:    map[string]*ast.FuncLit{},
+				}
+				scope.addFieldTypes(funcDeclaration.Recv)
+				scope.addFieldTypes(funcDeclaration.Type.Params)
+				scope.parseBlock(funcDeclaration.Body)
+			}
+		}
+	}
+}
+
+// FindRoutes returns the inferred routes of the controller funcDeclaration of packageName.
+func (parser *Parser) FindRoutes(packageName string, funcDeclaration *ast.FuncDecl) []*Route {
+	recv := ""
+	if funcDeclaration.Recv != nil && len(funcDeclaration.Recv.List) > 0 {
+		recv = receiverTypeName(funcDeclaration.Recv.List[0].Type)
+	}
+	name := funcDeclaration.Name.Name
+
+	var routes []*Route
+	for _, route := range parser.Routes {
+		if route.HandlerName != name {
+			continue
+		}
+		if route.HandlerRecv != recv || route.HandlerPackage != packageName {
+			continue
+		}
+
+		if route.Method == "" {
+			method := methodFromName(name)
+			if method == "" {
+				log.Printf("Can not infer the method of route %s of %s, skipped.\n", route.Path, name)
+				continue
+			}
+			inferred := *route
+			inferred.Method = method
+			route = &inferred
+		}
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// resolveRouteReceivers links the routes of handlers with an unknown receiver type, like
+// h.List of h := handlers.New(db), to the only annotated method of packages with their
+// name. Routes which fit several methods are skipped.
+func (parser *Parser) resolveRouteReceivers(packages []string) {
+	methods := map[string][]*Route{}
+	for _, packageName := range packages {
+		for _, astPackage := range parser.GetPackageAst(parser.GetRealPackagePath(packageName)) {
+			for _, astFile := range astPackage.Files {
+				for _, astDeclaration := range astFile.Decls {
+					funcDeclaration, ok := astDeclaration.(*ast.FuncDecl)
+					if !ok || funcDeclaration.Recv == nil || len(funcDeclaration.Recv.List) == 0 {
+						continue
+					}
+					if !parser.IsController(funcDeclaration, parser.ControllerClass) || !isAnnotated(funcDeclaration.Doc) {
+						continue
+					}
+					name := funcDeclaration.Name.Name
+					methods[name] = append(methods[name], &Route{
+						HandlerPackage: packageName,
+						HandlerRecv:    receiverTypeName(funcDeclaration.Recv.List[0].Type),
+						HandlerName:    name,
+					})
+				}
+			}
+		}
+	}
+
+	var routes []*Route
+	for _, route := range parser.Routes {
+		if route.HandlerRecv == "*" {
+			candidates := methods[route.HandlerName]
+			if len(candidates) > 1 {
+				log.Printf("Can not infer the controller of route %s, %d methods are named %s, skipped.\n", route.Path, len(candidates), route.HandlerName)
+				continue
+			}
+			if len(candidates) == 0 {
+				continue
+			}
+			route.HandlerPackage, route.HandlerRecv = candidates[0].HandlerPackage, candidates[0].HandlerRecv
+		}
+		routes = append(routes, route)
+	}
+	parser.Routes = routes
+}
+
+func (scope *routeScope) parseBlock(body ast.Node) {
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.AssignStmt:
+			// Closures passed to chains like r.Group("/v1", func(r chi.Router) {...})
+			// are parsed with their prefix by parseChain
+			parsed := false
+			for i, rhs := range node.Rhs {
+				if len(node.Lhs) != len(node.Rhs) {
+					break
+				}
+				ident, ok := node.Lhs[i].(*ast.Ident)
+				if !ok {
+					continue
+				}
+				if typePackage, typeName := scope.exprType(rhs); typeName != "" {
+					scope.types[ident.Name] = [2]string{typePackage, typeName}
+				}
+				if fn, ok := rhs.(*ast.FuncLit); ok {
+					scope.closures[ident.Name] = fn
+				}
+				if chain := scope.parseChain(rhs); chain != nil && (chain.isRouter || len(chain.handlers) > 0) {
+					parsed = true
+					if chain.isRouter {
+						scope.prefixes[ident.Name] = joinRoutePath(chain.prefix, chain.path)
+					}
+				}
+			}
+			return !parsed
+		case *ast.ValueSpec:
+			for _, ident := range node.Names {
+				if node.Type != nil {
+					if typePackage, typeName := scope.typeOf(node.Type); typeName != "" {
+						scope.types[ident.Name] = [2]string{typePackage, typeName}
+					}
+				}
+			}
+			for i, value := range node.Values {
+				if i < len(node.Names) {
+					if typePackage, typeName := scope.exprType(value); typeName != "" {
+						scope.types[node.Names[i].Name] = [2]string{typePackage, typeName}
+					}
+				}
+			}
+		case *ast.ExprStmt:
+			chain := scope.parseChain(node.X)
+			return chain == nil || (!chain.isRouter && len(chain.handlers) == 0)
+		case *ast.FuncLit:
+			// Closures passed to other calls, like fx.Invoke(func(r *gin.Engine) {...})
+			scope.parseRouterFunc(node, "")
+			return false
+		}
+		return true
+	})
+}
+
+// parseChain interprets a chain of calls on a router and registers the routes it declares.
+func (scope *routeScope) parseChain(expr ast.Expr) *routeChain {
+	var links []*ast.CallExpr
+	base := expr
+	for {
+		call, ok := base.(*ast.CallExpr)
+		if !ok {
+			break
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			break
+		}
+		links = append([]*ast.CallExpr{call}, links...)
+		base = selector.X
+	}
+	baseIdent, ok := base.(*ast.Ident)
+	if !ok || len(links) == 0 {
+		return nil
+	}
+
+	chain := &routeChain{prefix: scope.prefixes[baseIdent.Name]}
+	isHttpPackage := scope.imports[baseIdent.Name] == "net/http"
+	if !isHttpPackage && scope.imports[baseIdent.Name] != "" {
+		// Calls of other packages are not routers
+		return nil
+	}
+
+	for _, call := range links {
+		name := call.Fun.(*ast.SelectorExpr).Sel.Name
+		switch name {
+		case "HandleFunc", "Handle", "Method", "MethodFunc":
+			args := call.Args
+			// gin r.Handle("GET", path, h) and chi r.Method("GET", path, h)
+			if len(args) > 2 || name == "Method" || name == "MethodFunc" {
+				if len(args) < 3 {
+					return nil
+				}
+				method, ok := methodValue(args[0])
+				if !ok {
+					return nil
+				}
+				chain.methods = append(chain.methods, method)
+				args = args[1:]
+			}
+			if len(args) < 2 {
+				return nil
+			}
+			pattern, ok := stringValue(args[0])
+			if !ok {
+				return nil
+			}
+			method, routePath := splitMethodPattern(pattern)
+			if method != "" {
+				chain.methods = append(chain.methods, method)
+			}
+			chain.path += routePath
+			chain.handlers = scope.handlerArgs(args[1:])
+		case "Match":
+			// echo e.Match([]string{"GET", "POST"}, path, h)
+			if len(call.Args) < 3 {
+				return nil
+			}
+			if methods, ok := call.Args[0].(*ast.CompositeLit); ok {
+				for _, element := range methods.Elts {
+					if method, ok := methodValue(element); ok {
+						chain.methods = append(chain.methods, method)
+					}
+				}
+			}
+			routePath, ok := stringValue(call.Args[1])
+			if !ok {
+				return nil
+			}
+			chain.path += routePath
+			chain.handlers = scope.handlerArgs(call.Args[2:])
+		case "Path", "PathPrefix":
+			if len(call.Args) < 1 {
+				return nil
+			}
+			routePath, ok := stringValue(call.Args[0])
+			if !ok {
+				return nil
+			}
+			chain.path += routePath
+		case "Methods":
+			for _, arg := range call.Args {
+				if method, ok := methodValue(arg); ok {
+					chain.methods = append(chain.methods, method)
+				}
+			}
+		case "HandlerFunc", "Handler":
+			if len(call.Args) > 0 {
+				chain.handlers = call.Args[:1]
+			}
+		case "Subrouter":
+			chain.isRouter = true
+		case "Group", "Route":
+			// gin and echo r.Group("/v1", middlewares...), chi r.Route("/v1", func(r chi.Router) {...})
+			// and chi r.Group(func(r chi.Router) {...})
+			args := call.Args
+			if len(args) > 0 {
+				if routePath, ok := stringValue(args[0]); ok {
+					chain.path += routePath
+					args = args[1:]
+				}
+			}
+			chain.isRouter = true
+			for _, arg := range args {
+				if fn, ok := arg.(*ast.FuncLit); ok {
+					scope.parseRouterFunc(fn, joinRoutePath(chain.prefix, chain.path))
+				}
+			}
+		default:
+			// gin r.GET(path, h), echo e.GET(path, h), chi r.Get(path, h) and r.Any(path, h)
+			method := routeMethod(name)
+			if method == "" && name != "Any" {
+				continue
+			}
+			if len(call.Args) < 2 {
+				return nil
+			}
+			routePath, ok := stringValue(call.Args[0])
+			if !ok || (routePath != "" && !strings.HasPrefix(routePath, "/")) {
+				return nil
+			}
+			if method != "" {
+				chain.methods = append(chain.methods, method)
+			}
+			chain.path += routePath
+			chain.handlers = scope.handlerArgs(call.Args[1:])
+		}
+	}
+
+	if len(chain.handlers) > 0 {
+		scope.addRoutes(chain.methods, joinRoutePath(chain.prefix, chain.path), chain.handlers)
+	}
+	return chain
+}
+
+// parseRouterFunc collects the routes registered on the router parameter of fn.
+func (scope *routeScope) parseRouterFunc(fn *ast.FuncLit, prefix string) {
+	child := scope.child()
+	child.addFieldTypes(fn.Type.Params)
+	for _, field := range fn.Type.Params.List {
+		for _, ident := range field.Names {
+			child.prefixes[ident.Name] = prefix
+		}
+	}
+	child.parseBlock(fn.Body)
+}
+
+func (scope *routeScope) child() *routeScope {
+	child := &routeScope{
+		parser:      scope.parser,
+		packageName: scope.packageName,
+		imports:     scope.imports,
+		prefixes:    map[string]string{},
+		types:       map[string][2]string{},
+		closures:    map[string]*ast.FuncLit{},
+	}
+	for k, v := range scope.prefixes {
+		child.prefixes[k] = v
+	}
+	for k, v := range scope.types {
+		child.types[k] = v
+	}
+	for k, v := range scope.closures {
+		child.closures[k] = v
+	}
+	return child
+}
+
+// handlerArgs returns the handler of the handler arguments of a registration,
+// echo takes the middlewares after the handler while gin takes them before it.
+func (scope *routeScope) handlerArgs(args []ast.Expr) []ast.Expr {
+	if len(args) == 0 {
+		return nil
+	}
+	for _, importPath := range scope.imports {
+		if strings.Contains(importPath, "labstack/echo") {
+			return args[:1]
+		}
+	}
+	return args[len(args)-1:]
+}
+
+func (scope *routeScope) addRoutes(methods []string, routePath string, handlers []ast.Expr) {
+	if len(methods) == 0 {
+		methods = []string{""}
+	}
+	for _, handler := range handlers {
+		for _, candidate := range scope.resolveHandler(handler) {
+			for _, method := range methods {
+				route := *candidate
+				route.Method = strings.ToUpper(method)
+				route.Path = normalizeRoutePath(routePath)
+				scope.parser.Routes = append(scope.parser.Routes, &route)
+			}
+		}
+	}
+}
+
+// resolveHandler returns the functions handler may refer to. Method handlers of
+// variables of unknown types have the receiver "*".
+func (scope *routeScope) resolveHandler(handler ast.Expr) []*Route {
+	switch handler := handler.(type) {
+	case *ast.ParenExpr:
+		return scope.resolveHandler(handler.X)
+	case *ast.Ident:
+		if fn, ok := scope.closures[handler.Name]; ok {
+			return scope.resolveHandler(fn)
+		}
+		return []*Route{{HandlerPackage: scope.packageName, HandlerName: handler.Name}}
+	case *ast.SelectorExpr:
+		if ident, ok := handler.X.(*ast.Ident); ok {
+			if importPath, ok := scope.imports[ident.Name]; ok {
+				return []*Route{{HandlerPackage: importPath, HandlerName: handler.Sel.Name}}
+			}
+			if t, ok := scope.types[ident.Name]; ok {
+				return []*Route{{HandlerPackage: t[0], HandlerRecv: t[1], HandlerName: handler.Sel.Name}}
+			}
+		}
+		return []*Route{{HandlerRecv: "*", HandlerName: handler.Sel.Name}}
+	case *ast.CallExpr:
+		// Adapters and conversions like http.HandlerFunc(h.Get) wrap the handler,
+		// other calls like h.ListUsers(db) are handler factories
+		if len(handler.Args) > 0 && scope.isHandlerAdapter(handler.Fun) {
+			return scope.resolveHandler(handler.Args[len(handler.Args)-1])
+		}
+		return scope.resolveHandler(handler.Fun)
+	case *ast.FuncLit:
+		// Closures which only call the real handler
+		if handler.Body != nil && len(handler.Body.List) == 1 {
+			switch statement := handler.Body.List[0].(type) {
+			case *ast.ExprStmt:
+				if call, ok := statement.X.(*ast.CallExpr); ok {
+					return scope.resolveHandler(call.Fun)
+				}
+			case *ast.ReturnStmt:
+				if len(statement.Results) == 1 {
+					if call, ok := statement.Results[0].(*ast.CallExpr); ok {
+						return scope.resolveHandler(call.Fun)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isHandlerAdapter reports whether fun is one of the handlerAdapters or a func type
+// the handler is converted to.
+func (scope *routeScope) isHandlerAdapter(fun ast.Expr) bool {
+	switch fun := fun.(type) {
+	case *ast.ParenExpr:
+		return scope.isHandlerAdapter(fun.X)
+	case *ast.Ident:
+		return scope.parser.isFuncType(scope.packageName, fun.Name)
+	case *ast.SelectorExpr:
+		if ident, ok := fun.X.(*ast.Ident); ok {
+			if importPath, ok := scope.imports[ident.Name]; ok {
+				return handlerAdapters[importPath+"."+fun.Sel.Name] || scope.parser.isFuncType(importPath, fun.Sel.Name)
+			}
+		}
+	}
+	return false
+}
+
+func (scope *routeScope) addFieldTypes(fields *ast.FieldList) {
+	if fields == nil {
+		return
+	}
+	for _, field := range fields.List {
+		typePackage, typeName := scope.typeOf(field.Type)
+		if typeName == "" {
+			continue
+		}
+		for _, ident := range field.Names {
+			scope.types[ident.Name] = [2]string{typePackage, typeName}
+		}
+	}
+}
+
+// exprType returns the type of composite literals like &T{}, new(T) and of the
+// results of factories like NewT(db) and handlers.NewT(db).
+func (scope *routeScope) exprType(expr ast.Expr) (string, string) {
+	switch expr := expr.(type) {
+	case *ast.UnaryExpr:
+		if expr.Op == token.AND {
+			return scope.exprType(expr.X)
+		}
+	case *ast.CompositeLit:
+		if expr.Type != nil {
+			return scope.typeOf(expr.Type)
+		}
+	case *ast.CallExpr:
+		if ident, ok := expr.Fun.(*ast.Ident); ok && ident.Name == "new" && len(expr.Args) == 1 {
+			return scope.typeOf(expr.Args[0])
+		}
+		switch fun := expr.Fun.(type) {
+		case *ast.Ident:
+			return scope.parser.factoryType(scope.packageName, fun.Name)
+		case *ast.SelectorExpr:
+			if ident, ok := fun.X.(*ast.Ident); ok {
+				if importPath, ok := scope.imports[ident.Name]; ok {
+					return scope.parser.factoryType(importPath, fun.Sel.Name)
+				}
+			}
+		}
+	}
+	return "", ""
+}
+
+// factoryType returns the type of the single result of the function name of packageName.
+func (parser *Parser) factoryType(packageName, name string) (string, string) {
+	pkgRealPath := parser.CheckRealPackagePath(packageName)
+	if pkgRealPath == "" {
+		return "", ""
+	}
+	for _, astPackage := range parser.GetPackageAst(pkgRealPath) {
+		for _, astFile := range astPackage.Files {
+			for _, astDeclaration := range astFile.Decls {
+				funcDeclaration, ok := astDeclaration.(*ast.FuncDecl)
+				if !ok || funcDeclaration.Recv != nil || funcDeclaration.Name.Name != name {
+					continue
+				}
+				results := funcDeclaration.Type.Results
+				if results == nil || len(results.List) != 1 || len(results.List[0].Names) > 1 {
+					return "", ""
+				}
+				scope := &routeScope{parser: parser, packageName: packageName, imports: fileImports(astFile)}
+				return scope.typeOf(results.List[0].Type)
+			}
+		}
+	}
+	return "", ""
+}
+
+// isFuncType reports whether name is a func type declared by packageName.
+func (parser *Parser) isFuncType(packageName, name string) bool {
+	pkgRealPath := parser.CheckRealPackagePath(packageName)
+	if pkgRealPath == "" {
+		return false
+	}
+	for _, astPackage := range parser.GetPackageAst(pkgRealPath) {
+		for _, astFile := range astPackage.Files {
+			for _, astDeclaration := range astFile.Decls {
+				genDeclaration, ok := astDeclaration.(*ast.GenDecl)
+				if !ok || genDeclaration.Tok != token.TYPE {
+					continue
+				}
+				for _, astSpec := range genDeclaration.Specs {
+					if typeSpec := astSpec.(*ast.TypeSpec); typeSpec.Name.Name == name {
+						_, ok := typeSpec.Type.(*ast.FuncType)
+						return ok
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (scope *routeScope) typeOf(expr ast.Expr) (string, string) {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return scope.typeOf(expr.X)
+	case *ast.Ident:
+		return scope.packageName, expr.Name
+	case *ast.SelectorExpr:
+		if ident, ok := expr.X.(*ast.Ident); ok {
+			if importPath, ok := scope.imports[ident.Name]; ok {
+				return importPath, expr.Sel.Name
+			}
+		}
+	}
+	return "", ""
+}
+
+func receiverTypeName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	case *ast.IndexExpr:
+		return receiverTypeName(expr.X)
+	}
+	return ""
+}
+
+// fileImports maps the names of the imports of astFile to their import paths.
+func fileImports(astFile *ast.File) map[string]string {
+	imports := map[string]string{}
+	for _, astImport := range astFile.Imports {
+		importPath := strings.Trim(astImport.Path.Value, "\"")
+		var name string
+		if astImport.Name != nil {
+			name = astImport.Name.Name
+		} else {
+			name = importName(importPath)
+		}
+		if name != "_" && name != "." {
+			imports[name] = importPath
+		}
+	}
+	return imports
+}
+
+func stringValue(expr ast.Expr) (string, bool) {
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		if expr.Kind == token.STRING {
+			if value, err := strconv.Unquote(expr.Value); err == nil {
+				return value, true
+			}
+		}
+	case *ast.BinaryExpr:
+		if expr.Op == token.ADD {
+			x, okX := stringValue(expr.X)
+			y, okY := stringValue(expr.Y)
+			return x + y, okX && okY
+		}
+	case *ast.ParenExpr:
+		return stringValue(expr.X)
+	}
+	return "", false
+}
+
+// methodValue returns the http method of "POST" or http.MethodPost.
+func methodValue(expr ast.Expr) (string, bool) {
+	if selector, ok := expr.(*ast.SelectorExpr); ok && strings.HasPrefix(selector.Sel.Name, "Method") {
+		return strings.ToUpper(strings.TrimPrefix(selector.Sel.Name, "Method")), true
+	}
+	if method, ok := stringValue(expr); ok {
+		return strings.ToUpper(method), true
+	}
+	return "", false
+}
+
+// routeMethod returns the http method of router methods like GET of gin and echo or Get of chi.
+func routeMethod(name string) string {
+	for _, method := range Methods {
+		if name == method || name == method[:1]+strings.ToLower(method[1:]) {
+			return method
+		}
+	}
+	return ""
+}
+
+// methodFromName returns the http method a handler name like GetUsers starts with.
+func methodFromName(name string) string {
+	for _, method := range Methods {
+		prefix := method[:1] + strings.ToLower(method[1:])
+		if strings.HasPrefix(name, prefix) && (len(name) == len(prefix) || strings.ToUpper(name[len(prefix):len(prefix)+1]) == name[len(prefix):len(prefix)+1]) {
+			return method
+		}
+	}
+	return ""
+}
+
+// splitMethodPattern splits Go 1.22 ServeMux patterns like "GET example.com/user/{id}".
+func splitMethodPattern(pattern string) (string, string) {
+	method := ""
+	pattern = strings.TrimSpace(pattern)
+	if i := strings.IndexAny(pattern, " \t"); i != -1 {
+		method = strings.ToUpper(pattern[:i])
+		pattern = strings.TrimSpace(pattern[i:])
+	}
+	if !strings.HasPrefix(pattern, "/") {
+		if i := strings.Index(pattern, "/"); i != -1 {
+			// Drop the host
+			pattern = pattern[i:]
+		}
+	}
+	return method, pattern
+}
+
+// normalizeRoutePath converts path parameters like {id:[0-9]+}, {path...}, :id
+// and *path to {id} and {path}.
+func normalizeRoutePath(routePath string) string {
+	var result strings.Builder
+	for i := 0; i < len(routePath); i++ {
+		if (routePath[i] == ':' || routePath[i] == '*') && (i == 0 || routePath[i-1] == '/') {
+			end := strings.Index(routePath[i:], "/")
+			if end == -1 {
+				end = len(routePath)
+			} else {
+				end += i
+			}
+			if end > i+1 {
+				result.WriteString("{" + routePath[i+1:end] + "}")
+				i = end - 1
+				continue
+			}
+		}
+		if routePath[i] != '{' {
+			result.WriteByte(routePath[i])
+			continue
+		}
+		depth, end := 0, -1
+		for j := i; j < len(routePath); j++ {
+			if routePath[j] == '{' {
+				depth++
+			} else if routePath[j] == '}' {
+				depth--
+				if depth == 0 {
+					end = j
+					break
+				}
+			}
+		}
+		if end == -1 {
+			result.WriteString(routePath[i:])
+			break
+		}
+		name := strings.TrimSuffix(strings.SplitN(routePath[i+1:end], ":", 2)[0], "...")
+		if name != "$" {
+			result.WriteString("{" + strings.TrimSpace(name) + "}")
+		}
+		i = end
+	}
+	if result.Len() == 0 {
+		return "/"
+	}
+	return result.String()
+}
+
+func joinRoutePath(prefix, routePath string) string {
+	if prefix == "" {
+		return routePath
+	}
+	if routePath == "" {
+		return prefix
+	}
+	if !strings.HasPrefix(routePath, "/") {
+		routePath = "/" + routePath
+	}
+	return strings.TrimSuffix(prefix, "/") + routePath
+}
+
+// isAnnotated reports whether doc contains any swagger annotation.
+func isAnnotated(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, comment := range doc.List {
+		if strings.HasPrefix(strings.TrimSpace(strings.TrimLeft(comment.Text, "/")), "@") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package mswagger
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestParseRoutes(t *testing.T) {
+	tests := []struct {
+		packageName string
+		expected    []Route
+	}{
+		{
+			packageName: "testdata/routes",
+			expected: []Route{
+				{Method: "GET", Path: "/api/users", HandlerPackage: "testdata/routes", HandlerRecv: "UserController", HandlerName: "List"},
+				{Method: "DELETE", Path: "/api/users/{id}", HandlerPackage: "testdata/routes", HandlerName: "Delete"},
+				{Method: "GET", Path: "/api/users/{id}", HandlerPackage: "testdata/routes", HandlerRecv: "UserController", HandlerName: "Get"},
+				{Method: "PUT", Path: "/v1/users/{id}", HandlerPackage: "testdata/routes", HandlerRecv: "UserController", HandlerName: "Update"},
+			},
+		},
+		{
+			packageName: "testdata/frameworks",
+			expected: []Route{
+				{Method: "GET", Path: "/api/teams", HandlerPackage: "testdata/frameworks", HandlerName: "ListTeams"},
+				{Method: "POST", Path: "/api/teams/", HandlerPackage: "testdata/frameworks", HandlerName: "CreateTeam"},
+				{Method: "GET", Path: "/health/", HandlerPackage: "testdata/frameworks", HandlerName: "Health"},
+				{Method: "GET", Path: "/orders/{id}", HandlerPackage: "testdata/frameworks", HandlerName: "GetOrder"},
+				{Method: "PATCH", Path: "/orders/{id}", HandlerPackage: "testdata/frameworks", HandlerName: "SaveOrder"},
+				{Method: "PUT", Path: "/orders/{id}", HandlerPackage: "testdata/frameworks", HandlerName: "SaveOrder"},
+				{Method: "GET", Path: "/users/", HandlerPackage: "testdata/frameworks", HandlerName: "ListUsers"},
+				{Method: "DELETE", Path: "/users/{id}", HandlerPackage: "testdata/frameworks", HandlerName: "DeleteUser"},
+				{Method: "GET", Path: "/users/{id}", HandlerPackage: "testdata/frameworks", HandlerName: "GetUser"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		packagePath, err := filepath.Abs(test.packageName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parser := NewParser()
+		parser.PackagePathCache[test.packageName] = packagePath
+		parser.ParseRoutes(test.packageName)
+		// The files of a package are parsed in no particular order
+		sort.Slice(parser.Routes, func(i, j int) bool {
+			if parser.Routes[i].Path != parser.Routes[j].Path {
+				return parser.Routes[i].Path < parser.Routes[j].Path
+			}
+			return parser.Routes[i].Method < parser.Routes[j].Method
+		})
+
+		if len(parser.Routes) != len(test.expected) {
+			for _, route := range parser.Routes {
+				t.Logf("%+v", *route)
+			}
+			t.Errorf("%s: expected %d routes, got %d", test.packageName, len(test.expected), len(parser.Routes))
+			continue
+		}
+		for i, route := range parser.Routes {
+			if *route != test.expected[i] {
+				t.Errorf("%s: expected route %+v, got %+v", test.packageName, test.expected[i], *route)
+			}
+		}
+	}
+}
+
+func TestNormalizeRoutePath(t *testing.T) {
+	tests := []struct {
+		routePath string
+		expected  string
+	}{
+		{routePath: "", expected: "/"},
+		{routePath: "/users", expected: "/users"},
+		{routePath: "/users/:id", expected: "/users/{id}"},
+		{routePath: "/users/:id/posts/:postId", expected: "/users/{id}/posts/{postId}"},
+		{routePath: "/files/*path", expected: "/files/{path}"},
+		{routePath: "/users/{id}", expected: "/users/{id}"},
+		{routePath: "/users/{id:[0-9]+}", expected: "/users/{id}"},
+		{routePath: "/users/{id:[0-9]{1,3}}/posts", expected: "/users/{id}/posts"},
+		{routePath: "/files/{path...}", expected: "/files/{path}"},
+		{routePath: "/users/{$}", expected: "/users/"},
+		{routePath: "/time:now", expected: "/time:now"},
+	}
+
+	for _, test := range tests {
+		if actual := normalizeRoutePath(test.routePath); actual != test.expected {
+			t.Errorf("%q: expected %q, got %q", test.routePath, test.expected, actual)
+		}
+	}
+}
@@ -8,71 +8,118 @@ const (
 	ContentTypePlain             = "text/plain"
 	ContentTypeHtml              = "text/html"
 	ContentTypeMultiPartFormData = "multipart/form-data"
+	ContentTypeFormUrlEncoded    = "application/x-www-form-urlencoded"
 )
 
+// Extensions holds the "x-" properties of an object.
+type Extensions map[string]interface{}
+
 type SwaggerObject struct {
-	Swagger             string                       `json:"swagger"`
-	Info                *InfoObject                  `json:"info"`
-	Host                string                       `json:"host,omitempty"`
-	BasePath            string                       `json:"basePath,omitempty"`
-	Schemes             []string                     `json:"schemes,omitempty"`
-	Consumes            []string                     `json:"consumes,omitempty"`
-	Produces            []string                     `json:"produces,omitempty"`
-	Paths               map[string]*PathItemObject   `json:"paths"`
-	Definitions         map[string]*SchemaObject     `json:"definitions,omitempty"`
-	Parameters          map[string]interface{}       `json:"patameters,omitempty"`
-	Responses           map[string]interface{}       `json:"responses,omitempty"`
-	SecurityDefinitions map[string]interface{}       `json:"securityDefinitions,omitempty"`
-	Security            map[string][]string          `json:"security,omitempty"`
-	Tags                []*TagObject                 `json:"tags,omitempty"`
-	ExternalDocs        *ExternalDocumentationObject `json:"externalDocs,omitempty"`
+	Swagger             string                           `json:"swagger"`
+	Info                *InfoObject                      `json:"info"`
+	Host                string                           `json:"host,omitempty"`
+	BasePath            string                           `json:"basePath,omitempty"`
+	Schemes             []string                         `json:"schemes,omitempty"`
+	Consumes            []string                         `json:"consumes,omitempty"`
+	Produces            []string                         `json:"produces,omitempty"`
+	Paths               map[string]*PathItemObject       `json:"paths"`
+	Definitions         map[string]*SchemaObject         `json:"definitions,omitempty"`
+	Parameters          map[string]*ParameterObject      `json:"parameters,omitempty"`
+	Responses           map[string]*ResponseObject       `json:"responses,omitempty"`
+	SecurityDefinitions map[string]*SecuritySchemeObject `json:"securityDefinitions,omitempty"`
+	Security            []SecurityRequirementObject      `json:"security,omitempty"`
+	Tags                []*TagObject                     `json:"tags,omitempty"`
+	ExternalDocs        *ExternalDocumentationObject     `json:"externalDocs,omitempty"`
+	Extensions          Extensions                       `json:"-"`
+	// "x-" properties of the paths object
+	PathsExtensions Extensions `json:"-"`
 }
 
 type InfoObject struct {
-	Title          string   `json:"title"`
-	Description    string   `json:"description,omitempty"`
-	TermsOfService string   `json:"termsOfService,omitempty"`
-	Contact        *Contact `json:"contact,omitempty"`
-	License        *License `json:"license,omitempty"`
-	Version        string   `json:"version,omitempty"`
+	Title          string     `json:"title"`
+	Description    string     `json:"description,omitempty"`
+	TermsOfService string     `json:"termsOfService,omitempty"`
+	Contact        *Contact   `json:"contact,omitempty"`
+	License        *License   `json:"license,omitempty"`
+	Version        string     `json:"version,omitempty"`
+	Extensions     Extensions `json:"-"`
 }
 
 type Contact struct {
-	Name  string `json:"name,omitempty"`
-	URL   string `json:"url,omitempty"`
-	Email string `json:"email,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	URL        string     `json:"url,omitempty"`
+	Email      string     `json:"email,omitempty"`
+	Extensions Extensions `json:"-"`
 }
 
 type License struct {
-	Name string `json:"name,omitempty"`
-	URL  string `json:"url,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	URL        string     `json:"url,omitempty"`
+	Extensions Extensions `json:"-"`
 }
 
 type PathItemObject struct {
-	Ref        string           `json:"$ref,omitempty"`
-	Get        *OperationObject `json:"get,omitempty"`
-	Put        *OperationObject `json:"put,omitempty"`
-	Post       *OperationObject `json:"post,omitempty"`
-	Delete     *OperationObject `json:"delete,omitempty"`
-	Options    *OperationObject `json:"options,omitempty"`
-	Head       *OperationObject `json:"head,omitempty"`
-	Patch      *OperationObject `json:"patch,omitempty"`
-	Parameters []interface{}    `json:"parameters,omitempty"`
+	Ref        string             `json:"$ref,omitempty"`
+	Get        *OperationObject   `json:"get,omitempty"`
+	Put        *OperationObject   `json:"put,omitempty"`
+	Post       *OperationObject   `json:"post,omitempty"`
+	Delete     *OperationObject   `json:"delete,omitempty"`
+	Options    *OperationObject   `json:"options,omitempty"`
+	Head       *OperationObject   `json:"head,omitempty"`
+	Patch      *OperationObject   `json:"patch,omitempty"`
+	Parameters []*ParameterObject `json:"parameters,omitempty"`
+	Extensions Extensions         `json:"-"`
 }
 
+// ParameterObject/ReferenceObject
 type ParameterObject struct {
 	Ref              string        `json:"$ref,omitempty"`
-	Name             string        `json:"name"`
-	In               string        `json:"in"`
+	Name             string        `json:"name,omitempty"`
+	In               string        `json:"in,omitempty"`
 	Description      string        `json:"description,omitempty"`
 	Required         bool          `json:"required,omitempty"`
 	Schema           *SchemaObject `json:"schema,omitempty"`
 	Type             string        `json:"type,omitempty"`
 	Format           string        `json:"format,omitempty"`
 	AllowEmptyValue  bool          `json:"allowEmptyValue,omitempty"`
-	Items            []interface{} `json:"items,omitempty"`
-	CollectionFormat string        `json:"collectFormat,omitempty"`
-	// TODO ...
+	Items            *ItemsObject  `json:"items,omitempty"`
+	CollectionFormat string        `json:"collectionFormat,omitempty"`
+	Default          interface{}   `json:"default,omitempty"`
+	Maximum          *float64      `json:"maximum,omitempty"`
+	ExclusiveMaximum bool          `json:"exclusiveMaximum,omitempty"`
+	Minimum          *float64      `json:"minimum,omitempty"`
+	ExclusiveMinimum bool          `json:"exclusiveMinimum,omitempty"`
+	MaxLength        *int64        `json:"maxLength,omitempty"`
+	MinLength        *int64        `json:"minLength,omitempty"`
+	Pattern          string        `json:"pattern,omitempty"`
+	MaxItems         *int64        `json:"maxItems,omitempty"`
+	MinItems         *int64        `json:"minItems,omitempty"`
+	UniqueItems      bool          `json:"uniqueItems,omitempty"`
+	Enum             []interface{} `json:"enum,omitempty"`
+	MultipleOf       *float64      `json:"multipleOf,omitempty"`
+	Extensions       Extensions    `json:"-"`
+}
+
+// ItemsObject describes the items of non body array parameters and headers.
+type ItemsObject struct {
+	Type             string        `json:"type,omitempty"`
+	Format           string        `json:"format,omitempty"`
+	Items            *ItemsObject  `json:"items,omitempty"`
+	CollectionFormat string        `json:"collectionFormat,omitempty"`
+	Default          interface{}   `json:"default,omitempty"`
+	Maximum          *float64      `json:"maximum,omitempty"`
+	ExclusiveMaximum bool          `json:"exclusiveMaximum,omitempty"`
+	Minimum          *float64      `json:"minimum,omitempty"`
+	ExclusiveMinimum bool          `json:"exclusiveMinimum,omitempty"`
+	MaxLength        *int64        `json:"maxLength,omitempty"`
+	MinLength        *int64        `json:"minLength,omitempty"`
+	Pattern          string        `json:"pattern,omitempty"`
+	MaxItems         *int64        `json:"maxItems,omitempty"`
+	MinItems         *int64        `json:"minItems,omitempty"`
+	UniqueItems      bool          `json:"uniqueItems,omitempty"`
+	Enum             []interface{} `json:"enum,omitempty"`
+	MultipleOf       *float64      `json:"multipleOf,omitempty"`
+	Extensions       Extensions    `json:"-"`
 }
 
 type OperationObject struct {
@@ -83,13 +130,18 @@ type OperationObject struct {
 	OperationId  string                       `json:"operationId,omitempty"`
 	Consumes     []string                     `json:"consumes,omitempty"`
 	Produces     []string                     `json:"produces,omitempty"`
-	Parameters   []interface{}                `json:"parameters,omitempty"`
+	Parameters   []*ParameterObject           `json:"parameters,omitempty"`
 	Responses    map[string]*ResponseObject   `json:"responses,omitempty"`
 	Schemes      []string                     `json:"schemes,omitempty"`
 	Deprecated   bool                         `json:"deprecated,omitempty"`
-	Security     map[string][]string          `json:"security,omitempty"`
-	parser       *Parser
-	packageName  string
+	// An empty but non nil Security removes the top level security from the operation
+	Security   []SecurityRequirementObject `json:"security,omitempty"`
+	Extensions Extensions                  `json:"-"`
+	// "x-" properties of the responses object
+	ResponsesExtensions Extensions `json:"-"`
+	parser              *Parser
+	packageName         string
+	routed              bool
 }
 
 type ReferenceObject struct {
@@ -99,36 +151,119 @@ type ReferenceObject struct {
 // ResponseObject/ReferenceObject
 type ResponseObject struct {
 	Ref         string                 `json:"$ref,omitempty"`
-	Description string                 `json:"description,omitempty"`
+	Description string                 `json:"description"`
 	Schema      *SchemaObject          `json:"schema,omitempty"`
 	Headers     HeadersObject          `json:"headers,omitempty"`
 	Examples    map[string]interface{} `json:"examples,omitempty"`
+	Extensions  Extensions             `json:"-"`
 }
 
+// SchemaObject/ReferenceObject
 type SchemaObject struct {
-	Ref        string                 `json:"$ref,omitempty"`
-	Type       string                 `json:"type,omitempty"`
-	Required   []string               `json:"required,omitempty"`
-	Properties map[string]interface{} `json:"properties,omitempty"`
-	Items      *ReferenceObject       `json:"items,omitempty"`
+	Ref                  string                       `json:"$ref,omitempty"`
+	Format               string                       `json:"format,omitempty"`
+	Title                string                       `json:"title,omitempty"`
+	Description          string                       `json:"description,omitempty"`
+	Default              interface{}                  `json:"default,omitempty"`
+	MultipleOf           *float64                     `json:"multipleOf,omitempty"`
+	Maximum              *float64                     `json:"maximum,omitempty"`
+	ExclusiveMaximum     bool                         `json:"exclusiveMaximum,omitempty"`
+	Minimum              *float64                     `json:"minimum,omitempty"`
+	ExclusiveMinimum     bool                         `json:"exclusiveMinimum,omitempty"`
+	MaxLength            *int64                       `json:"maxLength,omitempty"`
+	MinLength            *int64                       `json:"minLength,omitempty"`
+	Pattern              string                       `json:"pattern,omitempty"`
+	MaxItems             *int64                       `json:"maxItems,omitempty"`
+	MinItems             *int64                       `json:"minItems,omitempty"`
+	UniqueItems          bool                         `json:"uniqueItems,omitempty"`
+	MaxProperties        *int64                       `json:"maxProperties,omitempty"`
+	MinProperties        *int64                       `json:"minProperties,omitempty"`
+	Required             []string                     `json:"required,omitempty"`
+	Enum                 []interface{}                `json:"enum,omitempty"`
+	Type                 string                       `json:"type,omitempty"`
+	Items                *SchemaObjectOrArray         `json:"items,omitempty"`
+	AllOf                []*SchemaObject              `json:"allOf,omitempty"`
+	Properties           map[string]*SchemaObject     `json:"properties,omitempty"`
+	AdditionalProperties *BoolOrSchemaObject          `json:"additionalProperties,omitempty"`
+	Discriminator        string                       `json:"discriminator,omitempty"`
+	ReadOnly             bool                         `json:"readOnly,omitempty"`
+	XML                  *XMLObject                   `json:"xml,omitempty"`
+	ExternalDocs         *ExternalDocumentationObject `json:"externalDocs,omitempty"`
+	Example              interface{}                  `json:"example,omitempty"`
+	Extensions           Extensions                   `json:"-"`
+}
+
+// SchemaObjectOrArray is either a schema or the schemas of the items of a tuple, as used by items.
+type SchemaObjectOrArray struct {
+	Schema  *SchemaObject
+	Schemas []*SchemaObject
+}
+
+// BoolOrSchemaObject is either a boolean or a schema, as used by additionalProperties.
+type BoolOrSchemaObject struct {
+	Allows bool
+	Schema *SchemaObject
+}
+
+type XMLObject struct {
+	Name       string     `json:"name,omitempty"`
+	Namespace  string     `json:"namespace,omitempty"`
+	Prefix     string     `json:"prefix,omitempty"`
+	Attribute  bool       `json:"attribute,omitempty"`
+	Wrapped    bool       `json:"wrapped,omitempty"`
+	Extensions Extensions `json:"-"`
 }
 
 type HeadersObject map[string]*HeaderObject
 
 type HeaderObject struct {
-	Description string `json:"description,omitempty"`
-	Type        string `json:"type,omitempty"`
+	Description      string        `json:"description,omitempty"`
+	Type             string        `json:"type,omitempty"`
+	Format           string        `json:"format,omitempty"`
+	Items            *ItemsObject  `json:"items,omitempty"`
+	CollectionFormat string        `json:"collectionFormat,omitempty"`
+	Default          interface{}   `json:"default,omitempty"`
+	Maximum          *float64      `json:"maximum,omitempty"`
+	ExclusiveMaximum bool          `json:"exclusiveMaximum,omitempty"`
+	Minimum          *float64      `json:"minimum,omitempty"`
+	ExclusiveMinimum bool          `json:"exclusiveMinimum,omitempty"`
+	MaxLength        *int64        `json:"maxLength,omitempty"`
+	MinLength        *int64        `json:"minLength,omitempty"`
+	Pattern          string        `json:"pattern,omitempty"`
+	MaxItems         *int64        `json:"maxItems,omitempty"`
+	MinItems         *int64        `json:"minItems,omitempty"`
+	UniqueItems      bool          `json:"uniqueItems,omitempty"`
+	Enum             []interface{} `json:"enum,omitempty"`
+	MultipleOf       *float64      `json:"multipleOf,omitempty"`
+	Extensions       Extensions    `json:"-"`
 }
 
+type SecuritySchemeObject struct {
+	Type             string            `json:"type"`
+	Description      string            `json:"description,omitempty"`
+	Name             string            `json:"name,omitempty"`
+	In               string            `json:"in,omitempty"`
+	Flow             string            `json:"flow,omitempty"`
+	AuthorizationUrl string            `json:"authorizationUrl,omitempty"`
+	TokenUrl         string            `json:"tokenUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes,omitempty"`
+	Extensions       Extensions        `json:"-"`
+}
+
+// SecurityRequirementObject maps names of security schemes to the required scopes.
+type SecurityRequirementObject map[string][]string
+
 type TagObject struct {
-	Name         string                      `json:"name"`
-	Description  string                      `json:"description,omitempty"`
-	ExternalDocs ExternalDocumentationObject `json:"externalDocs,omitempty"`
+	Name         string                       `json:"name"`
+	Description  string                       `json:"description,omitempty"`
+	ExternalDocs *ExternalDocumentationObject `json:"externalDocs,omitempty"`
+	Extensions   Extensions                   `json:"-"`
 }
 
 type ExternalDocumentationObject struct {
-	Description string `json:"description,omitempty"`
-	URL         string `json:"url"`
+	Description string     `json:"description,omitempty"`
+	URL         string     `json:"url"`
+	Extensions  Extensions `json:"-"`
 }
 
 // type Property struct {
@@ -0,0 +1,43 @@
+package discriminator
+
+// Event is sent to the subscribers.
+// @Discriminator kind
+// @Subtype created CreatedEvent
+// @Subtype deleted DeletedEvent
+type Event interface{}
+
+type CreatedEvent struct {
+	ID string `json:"id"`
+}
+
+type DeletedEvent struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// Shape is drawn on the canvas.
+// @Discriminator type
+type Shape interface {
+	Area() float64
+}
+
+type Circle struct {
+	Radius float64 `json:"radius"`
+}
+
+func (c Circle) Area() float64 {
+	return 3.14 * c.Radius * c.Radius
+}
+
+type Square struct {
+	Side float64 `json:"side"`
+}
+
+func (s *Square) Area() float64 {
+	return s.Side * s.Side
+}
+
+// Point has no Area.
+type Point struct {
+	X float64 `json:"x"`
+}
@@ -0,0 +1,23 @@
+package frameworks
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func ListUsers(w http.ResponseWriter, r *http.Request) {}
+
+func GetUser(w http.ResponseWriter, r *http.Request) {}
+
+func DeleteUser(w http.ResponseWriter, r *http.Request) {}
+
+func Chi(r chi.Router) {
+	r.Route("/users", func(r chi.Router) {
+		r.Get("/", ListUsers)
+		r.Group(func(r chi.Router) {
+			r.Get("/{id}", GetUser)
+			r.Method(http.MethodDelete, "/{id}", http.HandlerFunc(DeleteUser))
+		})
+	})
+}
@@ -0,0 +1,17 @@
+package frameworks
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+func logger(next echo.HandlerFunc) echo.HandlerFunc { return next }
+
+func GetOrder(c echo.Context) error { return nil }
+
+func SaveOrder(c echo.Context) error { return nil }
+
+func Echo(e *echo.Echo) {
+	orders := e.Group("/orders")
+	orders.GET("/:id", GetOrder, logger)
+	orders.Match([]string{"PUT", "PATCH"}, "/:id", SaveOrder)
+}
@@ -0,0 +1,18 @@
+package frameworks
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func auth(c *gin.Context) {}
+
+func ListTeams(c *gin.Context) {}
+
+func CreateTeam(c *gin.Context) {}
+
+func Gin(engine *gin.Engine) {
+	api := engine.Group("/api", auth)
+	teams := api.Group("/teams")
+	teams.GET("", auth, ListTeams)
+	teams.Handle("POST", "/", CreateTeam)
+}
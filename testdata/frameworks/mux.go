@@ -0,0 +1,11 @@
+package frameworks
+
+import (
+	"net/http"
+)
+
+func Health(w http.ResponseWriter, r *http.Request) {}
+
+func Mux(mux *http.ServeMux) {
+	mux.HandleFunc("GET /health/{$}", Health)
+}
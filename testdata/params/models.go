@@ -0,0 +1,14 @@
+package params
+
+// ListFilter filters the listed users.
+type ListFilter struct {
+	// Page of the list.
+	Page int      `form:"page"`
+	Sort string   `form:"sort" required:"true"`
+	Tags []string `form:"tags"`
+}
+
+// Tenant is sent by the gateway.
+type Tenant struct {
+	ID string `header:"X-Tenant-Id,required"`
+}
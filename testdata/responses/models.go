@@ -0,0 +1,13 @@
+package responses
+
+// User is a user of the service.
+type User struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Error is the body of failed requests.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/mux"
+	"go.uber.org/fx"
+)
+
+type UserController struct {
+	db *sql.DB
+}
+
+func NewUserController(db *sql.DB) *UserController {
+	return &UserController{db: db}
+}
+
+func (c *UserController) Get(w http.ResponseWriter, r *http.Request) {}
+
+func (c *UserController) List(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {}
+}
+
+func (c *UserController) Update(ctx *gin.Context) {}
+
+type HandlerFunc func(w http.ResponseWriter, r *http.Request)
+
+func Delete(w http.ResponseWriter, r *http.Request) {}
+
+func Router(db *sql.DB) {
+	users := NewUserController(db)
+
+	r := mux.NewRouter()
+	api := r.PathPrefix("/api").Subrouter()
+	api.Handle("/users/{id:[0-9]+}", http.HandlerFunc(users.Get)).Methods("GET")
+	api.HandleFunc("/users", users.List(db)).Methods(http.MethodGet)
+	api.Handle("/users/{id}", HandlerFunc(Delete)).Methods("DELETE")
+
+	fx.Invoke(func(engine *gin.Engine) {
+		v1 := engine.Group("/v1")
+		v1.PUT("/users/:id", users.Update)
+	})
+}
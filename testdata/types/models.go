@@ -0,0 +1,23 @@
+package types
+
+import (
+	"database/sql"
+	"time"
+
+	googleuuid "github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Status of the order.
+type Status string
+
+type Order struct {
+	ID        googleuuid.UUID `json:"id"`
+	Total     decimal.Decimal `json:"total"`
+	Note      sql.NullString  `json:"note"`
+	Status    Status          `json:"status"`
+	Quantity  uint16          `json:"quantity"`
+	Data      []byte          `json:"data"`
+	Metadata  interface{}     `json:"metadata"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
@@ -0,0 +1,207 @@
+package mswagger
+
+import (
+	"strings"
+)
+
+// SwaggerType is the type, format and minimum documenting a Go type. Nullable
+// types like sql.NullString are documented with x-nullable.
+type SwaggerType struct {
+	Type     string
+	Format   string
+	Minimum  *float64
+	Nullable bool
+}
+
+// TypeMappings maps Go types like int32 or time.Time to their swagger types, types of
+// other packages are keyed by their import path like github.com/google/uuid.UUID.
+// Types mapped to nil are known but have no swagger type, any value is valid for them.
+type TypeMappings map[string]*SwaggerType
+
+var unsignedMinimum = 0.0
+
+// DefaultTypeMappings returns the mappings of the builtin types and time.Time, refer to
+// builtin.go, and the well known types.
+func DefaultTypeMappings() TypeMappings {
+	mappings := WellKnownTypeMappings()
+	for typeName, swaggerType := range builtinTypeMappings() {
+		mappings[typeName] = swaggerType
+	}
+	return mappings
+}
+
+func builtinTypeMappings() TypeMappings {
+	return TypeMappings{
+		"bool":    {Type: "boolean"},
+		"uint":    {Type: "integer", Format: "int64", Minimum: &unsignedMinimum},
+		"uint8":   {Type: "integer", Format: "int32", Minimum: &unsignedMinimum},
+		"uint16":  {Type: "integer", Format: "int32", Minimum: &unsignedMinimum},
+		"uint32":  {Type: "integer", Format: "int64", Minimum: &unsignedMinimum},
+		"uint64":  {Type: "integer", Format: "int64", Minimum: &unsignedMinimum},
+		"uintptr": {Type: "integer", Format: "int64", Minimum: &unsignedMinimum},
+		"byte":    {Type: "integer", Format: "int32", Minimum: &unsignedMinimum},
+		"int":     {Type: "integer", Format: "int64"},
+		"int8":    {Type: "integer", Format: "int32"},
+		"int16":   {Type: "integer", Format: "int32"},
+		"int32":   {Type: "integer", Format: "int32"},
+		"int64":   {Type: "integer", Format: "int64"},
+		"rune":    {Type: "integer", Format: "int32"},
+		"float32": {Type: "number", Format: "float"},
+		"float64": {Type: "number", Format: "double"},
+		"string":  {Type: "string"},
+		// encoding/json can not marshal complex numbers, marshalers usually write them as strings
+		"complex64":  {Type: "string"},
+		"complex128": {Type: "string"},
+		// encoding/json encodes []byte as base64 strings
+		"[]byte":    {Type: "string", Format: "byte"},
+		"[]uint8":   {Type: "string", Format: "byte"},
+		"time.Time": {Type: "string", Format: "date-time"},
+		"file":      {Type: "file"},
+		"error":     nil,
+
+		"undefined": nil,
+	}
+}
+
+// WellKnownTypeMappings returns the mappings of the types of the standard library, the
+// uuid, decimal and null packages and the protobuf well known types.
+func WellKnownTypeMappings() TypeMappings {
+	mappings := TypeMappings{
+		"encoding/json.RawMessage": {},
+		"net.IP":                   {Type: "string"},
+		"net/url.URL":              {Type: "string", Format: "uri"},
+		"time.Duration":            {Type: "integer", Format: "int64"},
+
+		"database/sql.NullString":  {Type: "string", Nullable: true},
+		"database/sql.NullInt64":   {Type: "integer", Format: "int64", Nullable: true},
+		"database/sql.NullInt32":   {Type: "integer", Format: "int32", Nullable: true},
+		"database/sql.NullInt16":   {Type: "integer", Format: "int32", Nullable: true},
+		"database/sql.NullByte":    {Type: "integer", Format: "int32", Minimum: &unsignedMinimum, Nullable: true},
+		"database/sql.NullFloat64": {Type: "number", Format: "double", Nullable: true},
+		"database/sql.NullBool":    {Type: "boolean", Nullable: true},
+		"database/sql.NullTime":    {Type: "string", Format: "date-time", Nullable: true},
+
+		"github.com/google/uuid.UUID":     {Type: "string", Format: "uuid"},
+		"github.com/google/uuid.NullUUID": {Type: "string", Format: "uuid", Nullable: true},
+		"github.com/gofrs/uuid.UUID":      {Type: "string", Format: "uuid"},
+		"github.com/gofrs/uuid.NullUUID":  {Type: "string", Format: "uuid", Nullable: true},
+		"github.com/satori/go.uuid.UUID":  {Type: "string", Format: "uuid"},
+
+		// Decimals are marshalled as strings to keep their precision
+		"github.com/shopspring/decimal.Decimal":     {Type: "string", Format: "decimal"},
+		"github.com/shopspring/decimal.NullDecimal": {Type: "string", Format: "decimal", Nullable: true},
+
+		// protojson encodes the well known types as their JSON equivalents
+		"google.golang.org/protobuf/types/known/timestamppb.Timestamp":  {Type: "string", Format: "date-time"},
+		"google.golang.org/protobuf/types/known/durationpb.Duration":    {Type: "string"},
+		"google.golang.org/protobuf/types/known/fieldmaskpb.FieldMask":  {Type: "string"},
+		"google.golang.org/protobuf/types/known/emptypb.Empty":          {Type: "object"},
+		"google.golang.org/protobuf/types/known/anypb.Any":              {Type: "object"},
+		"google.golang.org/protobuf/types/known/structpb.Struct":        {Type: "object"},
+		"google.golang.org/protobuf/types/known/structpb.Value":         {},
+		"google.golang.org/protobuf/types/known/wrapperspb.StringValue": {Type: "string", Nullable: true},
+		"google.golang.org/protobuf/types/known/wrapperspb.BytesValue":  {Type: "string", Format: "byte", Nullable: true},
+		"google.golang.org/protobuf/types/known/wrapperspb.BoolValue":   {Type: "boolean", Nullable: true},
+		"google.golang.org/protobuf/types/known/wrapperspb.Int32Value":  {Type: "integer", Format: "int32", Nullable: true},
+		"google.golang.org/protobuf/types/known/wrapperspb.UInt32Value": {Type: "integer", Format: "int64", Minimum: &unsignedMinimum, Nullable: true},
+		// 64 bit integers are strings in protojson
+		"google.golang.org/protobuf/types/known/wrapperspb.Int64Value":  {Type: "string", Format: "int64", Nullable: true},
+		"google.golang.org/protobuf/types/known/wrapperspb.UInt64Value": {Type: "string", Format: "uint64", Nullable: true},
+		"google.golang.org/protobuf/types/known/wrapperspb.FloatValue":  {Type: "number", Format: "float", Nullable: true},
+		"google.golang.org/protobuf/types/known/wrapperspb.DoubleValue": {Type: "number", Format: "double", Nullable: true},
+		"github.com/golang/protobuf/ptypes/timestamp.Timestamp":         {Type: "string", Format: "date-time"},
+		"github.com/golang/protobuf/ptypes/duration.Duration":           {Type: "string"},
+	}
+
+	// The null types of all major versions of github.com/guregu/null
+	for _, importPath := range []string{"gopkg.in/guregu/null.v3", "gopkg.in/guregu/null.v4", "github.com/guregu/null/v5"} {
+		mappings[importPath+".String"] = &SwaggerType{Type: "string", Nullable: true}
+		mappings[importPath+".Int"] = &SwaggerType{Type: "integer", Format: "int64", Nullable: true}
+		mappings[importPath+".Float"] = &SwaggerType{Type: "number", Format: "double", Nullable: true}
+		mappings[importPath+".Bool"] = &SwaggerType{Type: "boolean", Nullable: true}
+		mappings[importPath+".Time"] = &SwaggerType{Type: "string", Format: "date-time", Nullable: true}
+	}
+	return mappings
+}
+
+func (mappings TypeMappings) IsBasicType(typeName string) bool {
+	_, ok := mappings[typeName]
+	return ok || strings.Contains(typeName, "interface")
+}
+
+func (mappings TypeMappings) IsBasicTypeSwaggerType(typeName string) bool {
+	swaggerType, ok := mappings[typeName]
+	return ok && swaggerType != nil || strings.Contains(typeName, "interface")
+}
+
+// Type returns the swagger type of typeName, empty for interfaces and unknown types.
+func (mappings TypeMappings) Type(typeName string) string {
+	if swaggerType := mappings[typeName]; swaggerType != nil {
+		return swaggerType.Type
+	}
+	return ""
+}
+
+// Format returns the swagger format of typeName.
+func (mappings TypeMappings) Format(typeName string) string {
+	if swaggerType := mappings[typeName]; swaggerType != nil {
+		return swaggerType.Format
+	}
+	return ""
+}
+
+// Minimum returns the minimum of typeName, like 0 for unsigned integers.
+func (mappings TypeMappings) Minimum(typeName string) *float64 {
+	if swaggerType := mappings[typeName]; swaggerType != nil && swaggerType.Minimum != nil {
+		minimum := *swaggerType.Minimum
+		return &minimum
+	}
+	return nil
+}
+
+// Nullable reports whether the values of typeName can be null.
+func (mappings TypeMappings) Nullable(typeName string) bool {
+	swaggerType := mappings[typeName]
+	return swaggerType != nil && swaggerType.Nullable
+}
+
+// Schema returns the schema of typeName, an empty schema for interfaces.
+func (mappings TypeMappings) Schema(typeName string) *SchemaObject {
+	schema := &SchemaObject{
+		Type:    mappings.Type(typeName),
+		Format:  mappings.Format(typeName),
+		Minimum: mappings.Minimum(typeName),
+	}
+	if mappings.Nullable(typeName) {
+		schema.Extensions = Extensions{"x-nullable": true}
+	}
+	return schema
+}
+
+// mappingsOf returns the swagger types of marshalers if typeName is one of them and
+// TypeMappings otherwise.
+func (parser *Parser) mappingsOf(typeName string) TypeMappings {
+	if _, ok := parser.swaggerTypes[typeName]; ok {
+		return parser.swaggerTypes
+	}
+	return parser.TypeMappings
+}
+
+// mappedType returns the key of the mapping of typeName used in packageName, like
+// github.com/google/uuid.UUID for uuid.UUID, or an empty string if it is not mapped.
+func (parser *Parser) mappedType(typeName string, packageName string) string {
+	if _, ok := parser.TypeMappings[typeName]; ok {
+		return typeName
+	}
+	typeNameParts := strings.Split(typeName, ".")
+	if len(typeNameParts) != 2 {
+		return ""
+	}
+	imports := parser.PackageImports[parser.CheckRealPackagePath(packageName)]
+	for _, importPath := range imports[typeNameParts[0]] {
+		if _, ok := parser.TypeMappings[importPath+"."+typeNameParts[1]]; ok {
+			return importPath + "." + typeNameParts[1]
+		}
+	}
+	return ""
+}
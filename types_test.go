@@ -0,0 +1,89 @@
+package mswagger
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func TestTypeMappingsSchema(t *testing.T) {
+	tests := []struct {
+		typeName string
+		expected string
+	}{
+		{typeName: "bool", expected: `{"type": "boolean"}`},
+		{typeName: "uint8", expected: `{"type": "integer", "format": "int32", "minimum": 0}`},
+		{typeName: "int", expected: `{"type": "integer", "format": "int64"}`},
+		{typeName: "float32", expected: `{"type": "number", "format": "float"}`},
+		{typeName: "complex128", expected: `{"type": "string"}`},
+		{typeName: "[]byte", expected: `{"type": "string", "format": "byte"}`},
+		{typeName: "time.Time", expected: `{"type": "string", "format": "date-time"}`},
+		{typeName: "interface{}", expected: `{}`},
+		{typeName: "encoding/json.RawMessage", expected: `{}`},
+		{typeName: "database/sql.NullInt64", expected: `{"type": "integer", "format": "int64", "x-nullable": true}`},
+		{typeName: "github.com/google/uuid.UUID", expected: `{"type": "string", "format": "uuid"}`},
+		{typeName: "gopkg.in/guregu/null.v4.Time", expected: `{"type": "string", "format": "date-time", "x-nullable": true}`},
+		{typeName: "google.golang.org/protobuf/types/known/wrapperspb.Int64Value", expected: `{"type": "string", "format": "int64", "x-nullable": true}`},
+	}
+
+	mappings := DefaultTypeMappings()
+	for _, test := range tests {
+		var expected interface{}
+		if err := json.Unmarshal([]byte(test.expected), &expected); err != nil {
+			t.Fatalf("%s: %v", test.typeName, err)
+		}
+		schema := mappings.Schema(test.typeName)
+		if !JsonEqual(schema, expected) {
+			actual, _ := json.Marshal(schema)
+			t.Errorf("%s: expected %s, got %s", test.typeName, test.expected, actual)
+		}
+	}
+}
+
+func TestParseMappedTypes(t *testing.T) {
+	tests := []struct {
+		property string
+		expected string
+	}{
+		{property: "id", expected: `{"type": "string", "format": "uuid"}`},
+		{property: "total", expected: `{"type": "string", "format": "decimal"}`},
+		{property: "note", expected: `{"type": "string", "x-nullable": true}`},
+		{property: "status", expected: `{"type": "string"}`},
+		{property: "quantity", expected: `{"type": "integer", "format": "int32", "minimum": 0}`},
+		{property: "data", expected: `{"type": "string", "format": "byte"}`},
+		{property: "metadata", expected: `{}`},
+		{property: "createdAt", expected: `{"type": "string", "format": "date-time"}`},
+	}
+
+	packageName := "testdata/types"
+	packagePath, err := filepath.Abs(packageName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parser := NewParser()
+	parser.Ignore = "swagger"
+	parser.PackagePathCache[packageName] = packagePath
+	parser.ParseTypeDefinitions(packageName)
+	parser.CurrentPackage = packageName
+	operation := NewOperationObject(parser, packageName)
+	if err := operation.ParseComment("@Success 200 {object} Order"); err != nil {
+		t.Fatal(err)
+	}
+	order := parser.Swagger.Definitions["testdata.types.Order"]
+	if order == nil {
+		t.Fatalf("definition testdata.types.Order is missing")
+	}
+
+	for _, test := range tests {
+		var expected interface{}
+		if err := json.Unmarshal([]byte(test.expected), &expected); err != nil {
+			t.Fatalf("%s: %v", test.property, err)
+		}
+		schema := order.Properties[test.property]
+		if !JsonEqual(schema, expected) {
+			actual, _ := json.Marshal(schema)
+			t.Errorf("%s: expected %s, got %s", test.property, test.expected, actual)
+		}
+	}
+}
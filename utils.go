@@ -1,5 +1,10 @@
 package mswagger
 
+import (
+	"encoding/json"
+	"reflect"
+)
+
 func IsInStringList(list []string, s string) bool {
 	for i, _ := range list {
 		if list[i] == s {
@@ -8,3 +13,40 @@ func IsInStringList(list []string, s string) bool {
 	}
 	return false
 }
+
+// Methods lists the http methods of PathItemObject in the order they are reported.
+var Methods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS", "HEAD"}
+
+// Operations returns the operations of the path item keyed by upper case http method.
+func (item *PathItemObject) Operations() map[string]*OperationObject {
+	operations := map[string]*OperationObject{}
+	if item == nil {
+		return operations
+	}
+	for method, operation := range map[string]*OperationObject{
+		"GET":     item.Get,
+		"POST":    item.Post,
+		"PUT":     item.Put,
+		"PATCH":   item.Patch,
+		"DELETE":  item.Delete,
+		"OPTIONS": item.Options,
+		"HEAD":    item.Head,
+	} {
+		if operation != nil {
+			operations[method] = operation
+		}
+	}
+	return operations
+}
+
+// JsonEqual reports whether a and b have the same JSON representation.
+func JsonEqual(a, b interface{}) bool {
+	var va, vb interface{}
+	if ba, err := json.Marshal(a); err != nil || json.Unmarshal(ba, &va) != nil {
+		return false
+	}
+	if bb, err := json.Marshal(b); err != nil || json.Unmarshal(bb, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,139 @@
+package mswagger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidationError is a problem of a swagger document at the JSON Pointer Path.
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("%s: %s", path, e.Message)
+}
+
+// ValidateSwagger validates swagger against the Swagger 2.0 JSON Schema and
+// the semantic rules of the specification which the schema can not express.
+func ValidateSwagger(swagger *SwaggerObject) ([]*ValidationError, error) {
+	data, err := json.Marshal(swagger)
+	if err != nil {
+		return nil, err
+	}
+	return ValidateSwaggerJson(data)
+}
+
+// ValidateSwaggerJson validates an encoded swagger document like ValidateSwagger.
+func ValidateSwaggerJson(data []byte) ([]*ValidationError, error) {
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	validator, err := newJsonSchemaValidator(swaggerSchemaJson, draft04SchemaJson)
+	if err != nil {
+		return nil, err
+	}
+	errs := validator.Validate("http://swagger.io/v2/schema.json", document)
+	errs = append(errs, validateRefs(document, document, "")...)
+
+	swagger := &SwaggerObject{}
+	if err := json.Unmarshal(data, swagger); err == nil {
+		errs = append(errs, validateOperations(swagger)...)
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Path < errs[j].Path
+	})
+	return errs, nil
+}
+
+// validateRefs reports local references which do not point into the document.
+func validateRefs(document, value interface{}, pointer string) []*ValidationError {
+	var errs []*ValidationError
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			childPointer := pointer + "/" + escapeJsonPointer(key)
+			if ref, ok := child.(string); ok && key == "$ref" {
+				if !strings.HasPrefix(ref, "#") {
+					// Remote references are not resolved
+					continue
+				}
+				if _, ok := JsonPointerGet(document, ref[1:]); !ok {
+					errs = append(errs, &ValidationError{Path: childPointer, Message: fmt.Sprintf("dangling reference %s", ref)})
+				}
+				continue
+			}
+			errs = append(errs, validateRefs(document, child, childPointer)...)
+		}
+	case []interface{}:
+		for i, child := range value {
+			errs = append(errs, validateRefs(document, child, fmt.Sprintf("%s/%d", pointer, i))...)
+		}
+	}
+	return errs
+}
+
+// validateOperations checks operationIds and the combination of parameters.
+func validateOperations(swagger *SwaggerObject) []*ValidationError {
+	var errs []*ValidationError
+	operationIds := map[string]string{}
+
+	for _, itemPath := range sortedPaths(swagger.Paths) {
+		item := swagger.Paths[itemPath]
+		operations := item.Operations()
+		for _, method := range Methods {
+			operation, ok := operations[method]
+			if !ok {
+				continue
+			}
+			pointer := "/paths/" + escapeJsonPointer(itemPath) + "/" + strings.ToLower(method)
+
+			if operation.OperationId != "" {
+				if other, ok := operationIds[operation.OperationId]; ok {
+					errs = append(errs, &ValidationError{Path: pointer + "/operationId", Message: fmt.Sprintf("duplicate operationId %q, already used by %s", operation.OperationId, other)})
+				} else {
+					operationIds[operation.OperationId] = method + " " + itemPath
+				}
+			}
+
+			// Parameters of the operation override those of the path item
+			parameters := map[string]*ParameterObject{}
+			for _, parameter := range append(append([]*ParameterObject{}, item.Parameters...), operation.Parameters...) {
+				if parameter.Ref != "" {
+					if resolved, ok := swagger.Parameters[strings.TrimPrefix(parameter.Ref, "#/parameters/")]; ok {
+						parameter = resolved
+					}
+				}
+				parameters[parameter.In+" "+parameter.Name] = parameter
+			}
+
+			var bodies, forms int
+			for _, parameter := range parameters {
+				switch parameter.In {
+				case "body":
+					bodies++
+				case "formData":
+					forms++
+				}
+			}
+			if bodies > 1 {
+				errs = append(errs, &ValidationError{Path: pointer + "/parameters", Message: "there can be one body parameter at most"})
+			}
+			if bodies > 0 && forms > 0 {
+				errs = append(errs, &ValidationError{Path: pointer + "/parameters", Message: "body and formData parameters can not be used together"})
+			}
+		}
+	}
+
+	return errs
+}
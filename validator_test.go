@@ -0,0 +1,86 @@
+package mswagger
+
+import (
+	"testing"
+)
+
+func TestValidateSwaggerJson(t *testing.T) {
+	tests := []struct {
+		name     string
+		swagger  string
+		expected []string
+	}{
+		{
+			name: "valid",
+			swagger: `{"swagger": "2.0", "info": {"title": "Users", "version": "1.0"},
+			  "paths": {"/users": {"get": {"operationId": "listUsers",
+			    "responses": {"200": {"description": "OK", "schema": {"$ref": "#/definitions/User"}}}}}},
+			  "definitions": {"User": {"type": "object"}}}`,
+		},
+		{
+			name: "dangling references",
+			swagger: `{"swagger": "2.0", "info": {"title": "Users", "version": "1.0"},
+			  "paths": {"/users/{id}": {"get": {"parameters": [{"$ref": "#/parameters/Id"}],
+			    "responses": {"200": {"description": "OK", "schema": {"$ref": "#/definitions/User"}}}}}},
+			  "parameters": {"Id": {"name": "id", "in": "path", "required": true, "type": "string"}},
+			  "definitions": {"Team": {"type": "object", "properties": {"lead": {"$ref": "#/definitions/User"}}}}}`,
+			expected: []string{
+				"/definitions/Team/properties/lead/$ref: dangling reference #/definitions/User",
+				"/paths/~1users~1{id}/get/responses/200/schema/$ref: dangling reference #/definitions/User",
+			},
+		},
+		{
+			name: "duplicate operationIds",
+			swagger: `{"swagger": "2.0", "info": {"title": "Users", "version": "1.0"},
+			  "paths": {
+			    "/users": {"get": {"operationId": "getUser", "responses": {"200": {"description": "OK"}}}},
+			    "/users/{id}": {"get": {"operationId": "getUser", "responses": {"200": {"description": "OK"}}}}}}`,
+			expected: []string{
+				`/paths/~1users~1{id}/get/operationId: duplicate operationId "getUser", already used by GET /users`,
+			},
+		},
+		{
+			name: "body and formData parameters",
+			swagger: `{"swagger": "2.0", "info": {"title": "Users", "version": "1.0"},
+			  "paths": {"/users": {
+			    "parameters": [{"name": "user", "in": "body", "schema": {"type": "object"}}],
+			    "post": {"parameters": [{"name": "other", "in": "body", "schema": {"type": "object"}}],
+			      "responses": {"200": {"description": "OK"}}},
+			    "put": {"parameters": [{"name": "name", "in": "formData", "type": "string"}],
+			      "responses": {"200": {"description": "OK"}}}}}}`,
+			expected: []string{
+				"/paths/~1users/post/parameters: there can be one body parameter at most",
+				"/paths/~1users/put/parameters: body and formData parameters can not be used together",
+			},
+		},
+		{
+			name:    "schema",
+			swagger: `{"swagger": "2.0", "info": {"title": "Users"}, "paths": {}}`,
+			expected: []string{
+				`/info: missing required property "version"`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		errs, err := ValidateSwaggerJson([]byte(test.swagger))
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		var actual []string
+		for _, e := range errs {
+			actual = append(actual, e.Error())
+		}
+		if len(actual) != len(test.expected) {
+			t.Errorf("%s: expected errors %q, got %q", test.name, test.expected, actual)
+			continue
+		}
+		for i := range actual {
+			if actual[i] != test.expected[i] {
+				t.Errorf("%s: expected errors %q, got %q", test.name, test.expected, actual)
+				break
+			}
+		}
+	}
+}